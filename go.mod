@@ -6,7 +6,9 @@ require (
 	github.com/go-pdf/fpdf v0.9.0
 	github.com/jackc/pgx/v5 v5.8.0
 	golang.org/x/crypto v0.42.0
+	golang.org/x/net v0.43.0
 	google.golang.org/grpc v1.75.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,202 @@
+package pdfgen
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AggregateFunc selects the summary a table column shows in its footer row.
+type AggregateFunc int
+
+const (
+	// AggregateNone leaves the footer cell empty (or holds the footer label).
+	AggregateNone AggregateFunc = iota
+	// AggregateSum adds the column's numeric cells.
+	AggregateSum
+	// AggregateAvg averages the column's numeric cells.
+	AggregateAvg
+	// AggregateMin shows the smallest numeric cell.
+	AggregateMin
+	// AggregateMax shows the largest numeric cell.
+	AggregateMax
+	// AggregateCount counts the column's non-empty cells.
+	AggregateCount
+	// AggregateCustom passes the column's numeric cells to ColumnDef.AggregateCustom.
+	AggregateCustom
+)
+
+// numericCell matches cell text like "14,123 mi", "$1,204.50" or "-3.5%":
+// a non-numeric prefix, a number with optional thousands separators, and a
+// non-numeric suffix.
+var numericCell = regexp.MustCompile(`^\s*([^\d.\-]*)(-?[\d,]*\.?\d+)(\D*?)\s*$`)
+
+// parseNumericCell extracts the number of a cell together with its prefix,
+// suffix and number of decimals. ok is false when the cell is not numeric.
+func parseNumericCell(s string) (v float64, prefix, suffix string, decimals int, ok bool) {
+	m := numericCell.FindStringSubmatch(s)
+	if m == nil {
+		return 0, "", "", 0, false
+	}
+	num := strings.ReplaceAll(m[2], ",", "")
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0, "", "", 0, false
+	}
+	if i := strings.IndexByte(num, '.'); i >= 0 {
+		decimals = len(num) - i - 1
+	}
+	return v, m[1], m[3], decimals, true
+}
+
+// columnAggregate accumulates the cells of one column.
+type columnAggregate struct {
+	col      ColumnDef
+	count    int // non-empty cells
+	n        int // numeric cells
+	sum      float64
+	min      float64
+	max      float64
+	values   []float64 // kept only for AggregateCustom
+	prefix   string    // affixes of the first numeric cell, e.g. "$" or " mi"
+	suffix   string
+	decimals int // most decimals seen in a numeric cell
+	affixed  bool
+	typed    bool // numbers come from Cell.Value rather than the text
+	duration bool // the first typed value was a time.Duration
+}
+
+// add accumulates a cell. Typed values count at full precision; the text
+// of the cell supplies the affixes and decimals of the result.
+func (a *columnAggregate) add(cell Cell) {
+	if strings.TrimSpace(cell.Text) == "" && cell.Value == nil {
+		return
+	}
+	a.count++
+	v, prefix, suffix, decimals, ok := parseNumericCell(cell.Text)
+	if raw, isNum := numberOf(cell.Value); isNum {
+		if !a.typed {
+			_, a.duration = cell.Value.(time.Duration)
+		}
+		v, ok, a.typed = raw, true, true
+	}
+	if !ok {
+		return
+	}
+	if !a.affixed {
+		a.prefix, a.suffix, a.affixed = prefix, suffix, true
+	}
+	if decimals > a.decimals {
+		a.decimals = decimals
+	}
+	if a.n == 0 || v < a.min {
+		a.min = v
+	}
+	if a.n == 0 || v > a.max {
+		a.max = v
+	}
+	a.n++
+	a.sum += v
+	if a.col.Aggregate == AggregateCustom {
+		a.values = append(a.values, v)
+	}
+}
+
+// text returns the formatted aggregate, or "" when there is nothing to show.
+func (a *columnAggregate) text() string {
+	if a.col.Aggregate == AggregateCount {
+		return strconv.Itoa(a.count)
+	}
+	if a.n == 0 {
+		return ""
+	}
+
+	var v float64
+	decimals := a.decimals
+	switch a.col.Aggregate {
+	case AggregateSum:
+		v = a.sum
+	case AggregateAvg:
+		v = a.sum / float64(a.n)
+		if decimals == 0 {
+			decimals = 1
+		}
+	case AggregateMin:
+		v = a.min
+	case AggregateMax:
+		v = a.max
+	case AggregateCustom:
+		if a.col.AggregateCustom == nil {
+			return ""
+		}
+		v = a.col.AggregateCustom(a.values)
+	default:
+		return ""
+	}
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return ""
+	}
+
+	if a.col.AggregateFormat != nil {
+		return a.col.AggregateFormat(v)
+	}
+	if a.typed && (a.col.FormatFunc != nil || a.col.Format.Kind != FormatNone || a.duration) {
+		// Format the result like the column's cells.
+		var raw any = v
+		if a.duration {
+			raw = time.Duration(v)
+		}
+		if s, ok := formatCell(a.col, raw); ok {
+			return s
+		}
+	}
+	return a.prefix + formatNumber(v, decimals) + a.suffix
+}
+
+// tableAggregates accumulates every column of a table.
+type tableAggregates struct {
+	cols []columnAggregate
+}
+
+func newTableAggregates(cols []ColumnDef) *tableAggregates {
+	a := &tableAggregates{cols: make([]columnAggregate, len(cols))}
+	for i, col := range cols {
+		a.cols[i].col = col
+	}
+	return a
+}
+
+// active reports whether any column has an aggregate.
+func (a *tableAggregates) active() bool {
+	for _, c := range a.cols {
+		if c.col.Aggregate != AggregateNone {
+			return true
+		}
+	}
+	return false
+}
+
+func (a *tableAggregates) add(row []Cell) {
+	for i := range a.cols {
+		if i < len(row) {
+			a.cols[i].add(row[i])
+		}
+	}
+}
+
+// row returns the summary row cells. label goes into the first column
+// without an aggregate; it is dropped when every column is aggregated.
+func (a *tableAggregates) row(label string) []string {
+	cells := make([]string, len(a.cols))
+	for i := range a.cols {
+		if a.cols[i].col.Aggregate != AggregateNone {
+			cells[i] = a.cols[i].text()
+		} else if label != "" {
+			cells[i] = label
+			label = ""
+		}
+	}
+	return cells
+}
@@ -0,0 +1,216 @@
+package pdfgen
+
+import "math"
+
+// autoColumnWidths sizes the Width == 0 columns to their content. Each such
+// column wants the width of its widest header or sample cell, clamped to
+// [MinWidth, MaxWidth], and header groups widen the columns they span to
+// fit their title. Spare width is shared by Flex weight; when the content
+// does not fit, the widest columns are shrunk first. It returns the widths
+// and the indexes of the columns left narrower than their cell content,
+// whether by MaxWidth or by shrinking.
+func (t *TableComponent) autoColumnWidths(doc *Document, usableWidth float64, sample [][]Cell, header []headerCell, headerFont, rowFont FontConfig, paddingH float64) ([]float64, []int) {
+	n := len(t.Columns)
+	widths := make([]float64, n)
+	want := make([]float64, n)    // clamped content width of each auto column
+	content := make([]float64, n) // unclamped content width
+	auto := make([]bool, n)
+	cm := doc.pdf.GetCellMargin()
+	available := usableWidth
+
+	for i, col := range t.Columns {
+		if col.Width > 0 {
+			widths[i] = col.Width
+			available -= col.Width
+			continue
+		}
+		auto[i] = true
+	}
+
+	// Column headers and single-column cells.
+	doc.applyFont(headerFont)
+	for _, h := range header {
+		if !h.group && auto[h.col] {
+			want[h.col] = math.Max(want[h.col], doc.stringWidth(h.title))
+		}
+	}
+	for _, row := range sample {
+		for i := 0; i < n && i < len(row); i++ {
+			cell := row[i]
+			if !auto[i] || cellSpan(cell, i, n) > 1 {
+				continue
+			}
+			font := cellFont(rowFont, t.Columns[i], cell)
+			doc.applyFont(font)
+			w := doc.stringWidth(cell.Text) + decorWidth(doc, cell, font)
+			want[i] = math.Max(want[i], w)
+		}
+	}
+	for i := range want {
+		if auto[i] {
+			want[i] += 2*cm + 2*paddingH
+			content[i] = want[i]
+			want[i] = clampWidth(want[i], t.Columns[i])
+		}
+	}
+
+	// Header groups wider than their columns widen the auto columns they
+	// span equally.
+	doc.applyFont(headerFont)
+	for _, h := range header {
+		if !h.group {
+			continue
+		}
+		need := doc.stringWidth(h.title) + 2*cm + 2*paddingH
+		have, autoCount := 0.0, 0
+		for c := h.col; c < h.col+h.span; c++ {
+			if auto[c] {
+				have += want[c]
+				autoCount++
+			} else {
+				have += widths[c]
+			}
+		}
+		if need > have && autoCount > 0 {
+			extra := (need - have) / float64(autoCount)
+			for c := h.col; c < h.col+h.span; c++ {
+				if auto[c] {
+					want[c] = clampWidth(want[c]+extra, t.Columns[c])
+				}
+			}
+		}
+	}
+
+	total := 0.0
+	for i := range want {
+		if auto[i] {
+			widths[i] = want[i]
+			total += want[i]
+		}
+	}
+	if total <= available {
+		growColumns(t.Columns, widths, auto, available-total)
+	} else {
+		shrinkColumns(t.Columns, widths, auto, total-available)
+	}
+
+	var truncated []int
+	for i := range widths {
+		if auto[i] && widths[i] < content[i]-0.01 {
+			truncated = append(truncated, i)
+		}
+	}
+	return widths, truncated
+}
+
+// clampWidth bounds w by the column's MinWidth and MaxWidth.
+func clampWidth(w float64, col ColumnDef) float64 {
+	if col.MaxWidth > 0 && w > col.MaxWidth {
+		w = col.MaxWidth
+	}
+	if w < col.MinWidth {
+		w = col.MinWidth
+	}
+	return w
+}
+
+// growColumns shares spare width among the auto columns by Flex weight,
+// handing the share of columns capped by MaxWidth to the others.
+func growColumns(cols []ColumnDef, widths []float64, auto []bool, spare float64) {
+	open := make([]bool, len(cols))
+	copy(open, auto)
+	for spare > 0.01 {
+		weight := 0.0
+		for i, col := range cols {
+			if open[i] {
+				weight += flexOf(col)
+			}
+		}
+		if weight == 0 {
+			return
+		}
+		unit := spare / weight
+		capped := false
+		for i, col := range cols {
+			if !open[i] {
+				continue
+			}
+			add := unit * flexOf(col)
+			if col.MaxWidth > 0 && widths[i]+add > col.MaxWidth {
+				add = math.Max(0, col.MaxWidth-widths[i])
+				open[i] = false
+				capped = true
+			}
+			widths[i] += add
+			spare -= add
+		}
+		if !capped {
+			return
+		}
+	}
+}
+
+func flexOf(col ColumnDef) float64 {
+	if col.Flex > 0 {
+		return col.Flex
+	}
+	return 1
+}
+
+// shrinkColumns removes excess width from the auto columns, widest first:
+// it lowers a common ceiling until the columns above it give up enough
+// width, never going below a column's MinWidth. If the minimum widths alone
+// do not fit, all auto columns are scaled down proportionally.
+func shrinkColumns(cols []ColumnDef, widths []float64, auto []bool, excess float64) {
+	// cut returns the width removed by capping the auto columns at level.
+	cut := func(level float64) float64 {
+		c := 0.0
+		for i, col := range cols {
+			if auto[i] {
+				c += widths[i] - math.Max(math.Min(widths[i], level), col.MinWidth)
+			}
+		}
+		return c
+	}
+
+	hi := 0.0
+	for i := range widths {
+		if auto[i] {
+			hi = math.Max(hi, widths[i])
+		}
+	}
+	if cut(0) < excess {
+		// Even at MinWidth the columns do not fit.
+		total, remaining := 0.0, -excess
+		for i, col := range cols {
+			if auto[i] {
+				remaining += widths[i]
+				widths[i] = col.MinWidth
+				total += col.MinWidth
+			}
+		}
+		if total > 0 && remaining > 0 {
+			for i := range widths {
+				if auto[i] {
+					widths[i] *= remaining / total
+				}
+			}
+		}
+		return
+	}
+
+	lo := 0.0
+	for k := 0; k < 50; k++ {
+		mid := (lo + hi) / 2
+		if cut(mid) > excess {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	for i, col := range cols {
+		if auto[i] {
+			widths[i] = math.Max(math.Min(widths[i], hi), col.MinWidth)
+		}
+	}
+}
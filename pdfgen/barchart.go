@@ -0,0 +1,390 @@
+package pdfgen
+
+import (
+	"fmt"
+	"math"
+)
+
+// BarChartComponent draws a bar chart of one or more series over a set of
+// categories, e.g. "Total distance per state" or miles per vehicle.
+//
+// Vertical charts have a fixed plot height and are kept together on one page.
+// Horizontal charts draw one band per category and page-break between bands,
+// repeating the value axis at the bottom of every page segment, so long lists
+// (all 48 states) flow like a table.
+type BarChartComponent struct {
+	Categories  []string             // category labels, one per bar group
+	Series      []ChartSeries        // Values[i] belongs to Categories[i]
+	Horizontal  bool                 // bars grow left→right with categories stacked top→bottom
+	Stacked     bool                 // stack series instead of drawing them side by side
+	Width       float64              // mm; 0 = full usable width
+	Height      float64              // mm plot height for vertical charts; default 60
+	BarSize     float64              // mm bar thickness for horizontal charts; default 4
+	Ticks       int                  // approximate number of value-axis intervals; default 5
+	ShowValues  bool                 // print the value at the end of each bar (stack total when Stacked)
+	ShowLegend  bool                 // draw a legend above the plot
+	ValueFormat func(float64) string // zero value → thousands separator, decimals as needed by the ticks
+	Font        FontConfig           // zero value → theme default at 7pt
+}
+
+// Render draws the chart and advances the Y cursor.
+func (c *BarChartComponent) Render(doc *Document) error {
+	if len(c.Categories) == 0 || len(c.Series) == 0 {
+		return nil
+	}
+	for _, s := range c.Series {
+		if len(s.Values) > len(c.Categories) {
+			return fmt.Errorf("pdfgen: BarChartComponent series %q has %d values for %d categories", s.Name, len(s.Values), len(c.Categories))
+		}
+	}
+
+	font := c.Font
+	if font.Family == "" {
+		font = doc.theme.DefaultFont
+		font.Size = 7
+	}
+	width := c.Width
+	if width == 0 {
+		width = doc.usableWidth()
+	}
+
+	lo, hi := c.valueRange()
+	axisMin, axisMax, step := niceScale(lo, hi, c.Ticks)
+	format := c.ValueFormat
+	if format == nil {
+		decimals := tickDecimals(step)
+		format = func(v float64) string { return formatNumber(v, decimals) }
+	}
+
+	if c.Horizontal {
+		c.renderHorizontal(doc, width, axisMin, axisMax, step, format, font)
+	} else {
+		c.renderVertical(doc, width, axisMin, axisMax, step, format, font)
+	}
+	return nil
+}
+
+// legendItems returns the legend entries, or nil when ShowLegend is off.
+func (c *BarChartComponent) legendItems(doc *Document) []legendItem {
+	if !c.ShowLegend {
+		return nil
+	}
+	items := make([]legendItem, len(c.Series))
+	for i, s := range c.Series {
+		items[i] = legendItem{Label: s.Name, Color: seriesColor(doc, s.Color, i)}
+	}
+	return items
+}
+
+// renderLegend starts a new page unless the legend plus the first required
+// mm of chart fit, draws the legend and moves the cursor below it.
+func (c *BarChartComponent) renderLegend(doc *Document, width, required float64, font FontConfig) {
+	items := c.legendItems(doc)
+	h := 0.0
+	if len(items) > 0 {
+		_, _, h = legendLayout(doc, items, width, font)
+		h += 2
+	}
+	doc.newPageIfNeeded(h + required)
+	if len(items) > 0 {
+		y := doc.currentY()
+		drawLegend(doc, items, doc.marginL, y, width, font)
+		doc.setY(y + h)
+	}
+}
+
+// valueRange returns the smallest and largest value the axis must show,
+// always including zero.
+func (c *BarChartComponent) valueRange() (lo, hi float64) {
+	for i := range c.Categories {
+		if c.Stacked {
+			pos, neg := 0.0, 0.0
+			for _, s := range c.Series {
+				if i < len(s.Values) {
+					if v := s.Values[i]; v >= 0 {
+						pos += v
+					} else {
+						neg += v
+					}
+				}
+			}
+			lo, hi = math.Min(lo, neg), math.Max(hi, pos)
+			continue
+		}
+		for _, s := range c.Series {
+			if i < len(s.Values) {
+				lo, hi = math.Min(lo, s.Values[i]), math.Max(hi, s.Values[i])
+			}
+		}
+	}
+	return lo, hi
+}
+
+// bar is one rectangle of a category band, in value-axis units.
+type bar struct {
+	from, to float64 // value range covered by the bar
+	slot     int     // side-by-side slot (0 when stacked)
+	color    Color
+}
+
+// barLabel is a value printed outside the end of a bar at value at.
+type barLabel struct {
+	v, at float64
+	slot  int
+}
+
+// bars returns the rectangles of category i and the values to print for
+// them: one per bar, or when stacked the stack's total, printed beyond its
+// positive bars, or its negative ones when it has none.
+func (c *BarChartComponent) bars(doc *Document, i int) ([]bar, []barLabel) {
+	var out []bar
+	pos, neg := 0.0, 0.0
+	for si, s := range c.Series {
+		if i >= len(s.Values) {
+			continue
+		}
+		v := s.Values[i]
+		color := seriesColor(doc, s.Color, si)
+		if !c.Stacked {
+			out = append(out, bar{from: 0, to: v, slot: si, color: color})
+			continue
+		}
+		if v >= 0 {
+			out = append(out, bar{from: pos, to: pos + v, color: color})
+			pos += v
+		} else {
+			out = append(out, bar{from: neg, to: neg + v, color: color})
+			neg += v
+		}
+	}
+	if !c.Stacked {
+		labels := make([]barLabel, len(out))
+		for k, b := range out {
+			labels[k] = barLabel{v: b.to, at: b.to, slot: b.slot}
+		}
+		return out, labels
+	}
+	end := pos
+	if pos == 0 {
+		end = neg
+	}
+	return out, []barLabel{{v: pos + neg, at: end}}
+}
+
+func (c *BarChartComponent) renderVertical(doc *Document, width, axisMin, axisMax, step float64, format func(float64) string, font FontConfig) {
+	plotH := c.Height
+	if plotH == 0 {
+		plotH = 60
+	}
+	const (
+		catLabelH = 5.0
+		valueH    = 3.5
+	)
+
+	doc.applyFont(font)
+	axisLabelW := 0.0
+	for v := axisMin; v <= axisMax+step/2; v += step {
+		axisLabelW = math.Max(axisLabelW, doc.stringWidth(format(v)))
+	}
+	axisLabelW += 2 * doc.pdf.GetCellMargin()
+
+	// Value labels sit above positive bars and below negative ones.
+	topPad, bottomPad := 0.0, 0.0
+	if c.ShowValues {
+		topPad = valueH
+		for i := range c.Categories {
+			_, labels := c.bars(doc, i)
+			for _, l := range labels {
+				if l.at < 0 {
+					bottomPad = valueH
+				}
+			}
+		}
+	}
+	c.renderLegend(doc, width, topPad+plotH+bottomPad+catLabelH, font)
+
+	startY := doc.currentY()
+	plotX := doc.marginL + axisLabelW
+	plotY := startY + topPad
+	plotW := width - axisLabelW
+	yAt := func(v float64) float64 {
+		return plotY + plotH - (v-axisMin)/(axisMax-axisMin)*plotH
+	}
+
+	// Gridlines and value-axis labels.
+	for v := axisMin; v <= axisMax+step/2; v += step {
+		y := yAt(v)
+		doc.applyColor(doc.theme.TableBorderColor)
+		doc.pdf.Line(plotX, y, plotX+plotW, y)
+		doc.applyTextColor(doc.theme.SecondaryText)
+		doc.pdf.SetXY(doc.marginL, y-valueH/2)
+		doc.cellFormat(axisLabelW, valueH, format(v), "", 0, "R", false)
+	}
+
+	groupW := plotW / float64(len(c.Categories))
+	slots := len(c.Series)
+	barW := groupW * 0.7 / float64(slots)
+	if c.Stacked {
+		slots = 1
+		barW = groupW * 0.6
+	}
+	for i, cat := range c.Categories {
+		groupX := plotX + float64(i)*groupW
+		left := groupX + (groupW-barW*float64(slots))/2
+		bars, labels := c.bars(doc, i)
+		for _, b := range bars {
+			x := left + float64(b.slot)*barW
+			y1, y2 := yAt(math.Max(b.from, b.to)), yAt(math.Min(b.from, b.to))
+			doc.applyColor(b.color)
+			doc.pdf.Rect(x, y1, barW, y2-y1, "F")
+		}
+		if c.ShowValues {
+			for _, l := range labels {
+				c.drawVerticalValue(doc, left+float64(l.slot)*barW, barW, l, yAt, valueH, format)
+			}
+		}
+
+		doc.applyFont(font)
+		doc.applyTextColor(doc.theme.PrimaryText)
+		doc.pdf.SetXY(groupX, plotY+plotH+bottomPad)
+		doc.cellFormat(groupW, catLabelH, truncateText(doc, cat, groupW-2*doc.pdf.GetCellMargin()), "", 0, "C", false)
+	}
+
+	// Zero baseline.
+	doc.applyColor(doc.theme.SecondaryText)
+	doc.pdf.Line(plotX, yAt(0), plotX+plotW, yAt(0))
+
+	doc.setY(plotY + plotH + bottomPad + catLabelH + 2)
+}
+
+// drawVerticalValue prints a value label just outside the end of a
+// vertical bar.
+func (c *BarChartComponent) drawVerticalValue(doc *Document, x, w float64, l barLabel, yAt func(float64) float64, h float64, format func(float64) string) {
+	doc.applyTextColor(doc.theme.PrimaryText)
+	y := yAt(l.at) - h
+	if l.at < 0 {
+		y = yAt(l.at)
+	}
+	doc.pdf.SetXY(x-w, y)
+	doc.cellFormat(3*w, h, format(l.v), "", 0, "C", false)
+}
+
+func (c *BarChartComponent) renderHorizontal(doc *Document, width, axisMin, axisMax, step float64, format func(float64) string, font FontConfig) {
+	barSize := c.BarSize
+	if barSize == 0 {
+		barSize = 4
+	}
+	const (
+		bandPad = 1.5
+		axisH   = 5.0
+	)
+	slots := len(c.Series)
+	if c.Stacked {
+		slots = 1
+	}
+	bandH := float64(slots)*barSize + 2*bandPad
+
+	doc.applyFont(font)
+	cm := doc.pdf.GetCellMargin()
+	labelW := 0.0
+	for _, cat := range c.Categories {
+		labelW = math.Max(labelW, doc.stringWidth(cat))
+	}
+	labelW = math.Min(labelW+2*cm, width*0.35)
+
+	// Value labels sit right of positive bars and left of negative ones,
+	// so reserve room for each side outside the plot.
+	valueW, negValueW := 0.0, 0.0
+	if c.ShowValues {
+		for i := range c.Categories {
+			_, labels := c.bars(doc, i)
+			for _, l := range labels {
+				w := doc.stringWidth(format(l.v)) + 2*cm
+				if l.at < 0 {
+					negValueW = math.Max(negValueW, w)
+				} else {
+					valueW = math.Max(valueW, w)
+				}
+			}
+		}
+	}
+
+	plotX := doc.marginL + labelW + negValueW
+	plotW := width - labelW - negValueW - valueW
+	xAt := func(v float64) float64 {
+		return plotX + (v-axisMin)/(axisMax-axisMin)*plotW
+	}
+
+	// drawGrid draws the gridlines behind the bands between fromY and toY
+	// and the value axis beneath them.
+	drawGrid := func(fromY, toY float64) {
+		for v := axisMin; v <= axisMax+step/2; v += step {
+			x := xAt(v)
+			doc.applyColor(doc.theme.TableBorderColor)
+			doc.pdf.Line(x, fromY, x, toY)
+			doc.applyFont(font)
+			doc.applyTextColor(doc.theme.SecondaryText)
+			doc.pdf.SetXY(x-10, toY)
+			doc.cellFormat(20, axisH, format(v), "", 0, "C", false)
+		}
+	}
+
+	c.renderLegend(doc, width, bandH+axisH, font)
+	for i := 0; i < len(c.Categories); {
+		if i > 0 {
+			doc.addPage()
+		}
+		// Each page segment holds as many bands as fit above its axis.
+		segStart := doc.currentY()
+		n := int((doc.pageBottom() - segStart - axisH) / bandH)
+		n = min(max(n, 1), len(c.Categories)-i)
+		segEnd := segStart + float64(n)*bandH
+		drawGrid(segStart, segEnd)
+
+		for ; n > 0; i, n = i+1, n-1 {
+			y := doc.currentY()
+
+			doc.applyFont(font)
+			doc.applyTextColor(doc.theme.PrimaryText)
+			doc.pdf.SetXY(doc.marginL, y)
+			doc.cellFormat(labelW, bandH, truncateText(doc, c.Categories[i], labelW-2*cm), "", 0, "L", false)
+
+			bars, labels := c.bars(doc, i)
+			for _, b := range bars {
+				by := y + bandPad + float64(b.slot)*barSize
+				x1, x2 := xAt(math.Min(b.from, b.to)), xAt(math.Max(b.from, b.to))
+				doc.applyColor(b.color)
+				doc.pdf.Rect(x1, by, x2-x1, barSize, "F")
+			}
+			if c.ShowValues {
+				for _, l := range labels {
+					c.drawHorizontalValue(doc, l, y+bandPad+float64(l.slot)*barSize, barSize, xAt, format, font)
+				}
+			}
+			doc.setY(y + bandH)
+		}
+
+		// Zero baseline.
+		doc.applyColor(doc.theme.SecondaryText)
+		doc.pdf.Line(xAt(0), segStart, xAt(0), segEnd)
+		doc.setY(segEnd + axisH)
+	}
+	doc.setY(doc.currentY() + 2)
+}
+
+// drawHorizontalValue prints a value label just outside the end of a
+// horizontal bar.
+func (c *BarChartComponent) drawHorizontalValue(doc *Document, l barLabel, y, h float64, xAt func(float64) float64, format func(float64) string, font FontConfig) {
+	doc.applyFont(font)
+	doc.applyTextColor(doc.theme.PrimaryText)
+	text := format(l.v)
+	w := doc.stringWidth(text) + 2*doc.pdf.GetCellMargin()
+	x := xAt(l.at)
+	align := "L"
+	if l.at < 0 {
+		x -= w
+		align = "R"
+	}
+	doc.pdf.SetXY(x, y)
+	doc.cellFormat(w, h, text, "", 0, align, false)
+}
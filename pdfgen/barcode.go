@@ -0,0 +1,206 @@
+package pdfgen
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// BarcodeType selects the symbology of a BarcodeComponent.
+type BarcodeType int
+
+const (
+	Code128 BarcodeType = iota // printable ASCII, compact; digit runs packed two per symbol
+	Code39                     // 0-9, A-Z, space and - . $ / + %
+)
+
+// BarcodeComponent renders a linear barcode encoded by pdfgen itself, drawn
+// as vector bars, e.g. a VIN or unit number on a DVIR printout. It sits in
+// the content flow, or floats at a page corner like LogoComponent when
+// Position is set.
+type BarcodeComponent struct {
+	Data        string
+	Type        BarcodeType // default Code128
+	Width       float64     // mm, quiet zones included; 0 → 0.25 mm per module
+	Height      float64     // mm, bars; default 12
+	QuietZone   int         // light modules on each side; 0 → 10, negative → none
+	Color       Color       // bars; zero value → black
+	Caption     string      // text centered under the bars, usually Data
+	CaptionFont FontConfig  // zero value → theme DefaultFont at 7pt
+	Align       string      // in the flow: "L" | "C" | "R"; default "L"
+	Position    string      // "" in the flow; "top-left" | "top-right" | "top-center" floats
+	OffsetX     float64     // additional X offset in mm
+	OffsetY     float64     // additional Y offset in mm
+}
+
+// Render draws the barcode. In the flow it moves to a new page when it does
+// not fit and advances Y below the caption; floating, Y is not advanced.
+func (b *BarcodeComponent) Render(doc *Document) error {
+	if b.Data == "" {
+		return fmt.Errorf("pdfgen: BarcodeComponent requires Data")
+	}
+	var widths []int
+	var err error
+	switch b.Type {
+	case Code39:
+		widths, err = encodeCode39(b.Data)
+	default:
+		widths, err = encodeCode128(b.Data)
+	}
+	if err != nil {
+		return fmt.Errorf("pdfgen: BarcodeComponent: %w", err)
+	}
+	quiet := b.QuietZone
+	if quiet == 0 {
+		quiet = 10
+	}
+	quiet = max(quiet, 0)
+	modules := 2 * quiet
+	for _, w := range widths {
+		modules += w
+	}
+	m := 0.25
+	if b.Width > 0 {
+		m = b.Width / float64(modules)
+	}
+	w := float64(modules) * m
+	h := b.Height
+	if h == 0 {
+		h = 12
+	}
+
+	x, y, caption := placeSymbol(doc, b.Position, b.Align, b.OffsetX, b.OffsetY, w, h, b.Caption, b.CaptionFont)
+	doc.applyColor(b.Color)
+	x += float64(quiet) * m
+	for i, bw := range widths {
+		if i%2 == 0 {
+			doc.pdf.Rect(x, y, float64(bw)*m, h, "F")
+		}
+		x += float64(bw) * m
+	}
+	caption()
+	return nil
+}
+
+// code128Patterns are the bar and space widths of the Code 128 symbols,
+// values 0–105 and the stop pattern.
+var code128Patterns = [107]string{
+	"212222", "222122", "222221", "121223", "121322", "131222", "122213", "122312", "132212", "221213",
+	"221312", "231212", "112232", "122132", "122231", "113222", "123122", "123221", "223211", "221132",
+	"221231", "213212", "223112", "312131", "311222", "321122", "321221", "312212", "322112", "322211",
+	"212123", "212321", "232121", "111323", "131123", "131321", "112313", "132113", "132311", "211313",
+	"231113", "231311", "112133", "112331", "132131", "113123", "113321", "133121", "313121", "211331",
+	"231131", "213113", "213311", "213131", "311123", "311321", "331121", "312113", "312311", "332111",
+	"314111", "221411", "431111", "111224", "111422", "121124", "121421", "141122", "141221", "112214",
+	"112412", "122114", "122411", "142112", "142211", "241211", "221114", "413111", "241112", "134111",
+	"111242", "121142", "121241", "114212", "124112", "124211", "411212", "421112", "421211", "212141",
+	"214121", "412121", "111143", "111341", "131141", "114113", "114311", "411113", "411311", "113141",
+	"114131", "311141", "411131", "211412", "211214", "211232", "2331112",
+}
+
+const (
+	code128CodeC  = 99
+	code128CodeB  = 100
+	code128StartB = 104
+	code128StartC = 105
+	code128Stop   = 106
+)
+
+// encodeCode128 returns the bar and space widths, in modules, of data in
+// Code 128: code set B, switching to C for runs of digits long enough to
+// save space.
+func encodeCode128(data string) ([]int, error) {
+	for i := 0; i < len(data); i++ {
+		if data[i] < 32 || data[i] > 126 {
+			r, _ := utf8.DecodeRuneInString(data[i:])
+			return nil, fmt.Errorf("Code 128 cannot encode %q", r)
+		}
+	}
+	digits := func(i int) int {
+		n := 0
+		for i+n < len(data) && data[i+n] >= '0' && data[i+n] <= '9' {
+			n++
+		}
+		return n
+	}
+
+	var values []int
+	setC := digits(0) >= 4 || (len(data) == 2 && digits(0) == 2)
+	if setC {
+		values = append(values, code128StartC)
+	} else {
+		values = append(values, code128StartB)
+	}
+	for i := 0; i < len(data); {
+		if setC {
+			if digits(i) >= 2 {
+				values = append(values, int(data[i]-'0')*10+int(data[i+1]-'0'))
+				i += 2
+				continue
+			}
+			values = append(values, code128CodeB)
+			setC = false
+			continue
+		}
+		if d := digits(i); d >= 6 || (d >= 4 && i+d == len(data)) {
+			if d%2 == 1 {
+				values = append(values, int(data[i])-32)
+				i++
+			}
+			values = append(values, code128CodeC)
+			setC = true
+			continue
+		}
+		values = append(values, int(data[i])-32)
+		i++
+	}
+	sum := values[0]
+	for i, v := range values[1:] {
+		sum += (i + 1) * v
+	}
+	values = append(values, sum%103, code128Stop)
+
+	var widths []int
+	for _, v := range values {
+		for _, c := range code128Patterns[v] {
+			widths = append(widths, int(c-'0'))
+		}
+	}
+	return widths, nil
+}
+
+// code39Chars and code39Patterns give the narrow (n) and wide (w) bars and
+// spaces of each Code 39 character, bar first.
+const code39Chars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ-. $/+%*"
+
+var code39Patterns = [44]string{
+	"nnnwwnwnn", "wnnwnnnnw", "nnwwnnnnw", "wnwwnnnnn", "nnnwwnnnw", "wnnwwnnnn", "nnwwwnnnn", "nnnwnnwnw", "wnnwnnwnn", "nnwwnnwnn",
+	"wnnnnwnnw", "nnwnnwnnw", "wnwnnwnnn", "nnnnwwnnw", "wnnnwwnnn", "nnwnwwnnn", "nnnnnwwnw", "wnnnnwwnn", "nnwnnwwnn", "nnnnwwwnn",
+	"wnnnnnnww", "nnwnnnnww", "wnwnnnnwn", "nnnnwnnww", "wnnnwnnwn", "nnwnwnnwn", "nnnnnnwww", "wnnnnnwwn", "nnwnnnwwn", "nnnnwnwwn",
+	"wwnnnnnnw", "nwwnnnnnw", "wwwnnnnnn", "nwnnwnnnw", "wwnnwnnnn", "nwwnwnnnn", "nwnnnnwnw", "wwnnnnwnn", "nwwnnnwnn", "nwnwnwnnn",
+	"nwnwnnnwn", "nwnnnwnwn", "nnnwnwnwn", "nwnnwnwnn",
+}
+
+// encodeCode39 returns the bar and space widths, in modules, of data in
+// Code 39 between * start and stop characters, with wide elements three
+// times the narrow.
+func encodeCode39(data string) ([]int, error) {
+	var widths []int
+	for i, c := range "*" + data + "*" {
+		k := strings.IndexRune(code39Chars, c)
+		if k < 0 || (c == '*' && i > 0 && i <= len(data)) {
+			return nil, fmt.Errorf("Code 39 cannot encode %q", c)
+		}
+		if i > 0 {
+			widths = append(widths, 1) // gap between characters
+		}
+		for _, e := range code39Patterns[k] {
+			if e == 'w' {
+				widths = append(widths, 3)
+			} else {
+				widths = append(widths, 1)
+			}
+		}
+	}
+	return widths, nil
+}
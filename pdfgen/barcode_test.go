@@ -0,0 +1,109 @@
+package pdfgen
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// widthString writes bar and space widths as digits, e.g. "211214".
+func widthString(widths []int) string {
+	var sb strings.Builder
+	for _, w := range widths {
+		sb.WriteString(strconv.Itoa(w))
+	}
+	return sb.String()
+}
+
+func TestEncodeCode128(t *testing.T) {
+	// Width sequences match an independent Code 128 encoder. The checksum
+	// is the start value plus each symbol value times its position, mod 103.
+	tests := []struct {
+		data     string
+		want     string
+		checksum int
+	}{
+		{
+			// Start B, P J J 1 2 3 C: 104 + 48 + 2·42 + 3·42 + 4·17 + 5·18 + 6·19 + 7·35 = 879 → 55
+			data:     "PJJ123C",
+			want:     "211214" + "313121" + "112133" + "112133" + "123221" + "223211" + "221132" + "131321" + "311321" + "2331112",
+			checksum: 55,
+		},
+		{
+			// Start C, 12 34 56 78 90: 105 + 12 + 2·34 + 3·56 + 4·78 + 5·90 = 1115 → 85
+			data:     "1234567890",
+			want:     "211232" + "112232" + "131123" + "331121" + "241112" + "214121" + "124211" + "2331112",
+			checksum: 85,
+		},
+		{
+			// Start B, A B C -, Code C, 12 34: 104 + 33 + 2·34 + 3·35 + 4·13 + 5·99 + 6·12 + 7·34 = 1167 → 34
+			data:     "ABC-1234",
+			want:     "211214" + "111323" + "131123" + "131321" + "122132" + "113141" + "112232" + "131123" + "131123" + "2331112",
+			checksum: 34,
+		},
+		{
+			// Start C, 12: 105 + 12 = 117 → 14
+			data:     "12",
+			want:     "211232" + "112232" + "122231" + "2331112",
+			checksum: 14,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			widths, err := encodeCode128(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := widthString(widths); got != tt.want {
+				t.Errorf("widths\n got %s\nwant %s", got, tt.want)
+			}
+			check := widthString(widths[len(widths)-13 : len(widths)-7])
+			if got := code128Patterns[tt.checksum]; check != got {
+				t.Errorf("checksum symbol %s, want %s (value %d)", check, got, tt.checksum)
+			}
+		})
+	}
+}
+
+func TestEncodeCode39(t *testing.T) {
+	// Width sequences match an independent Code 39 encoder, with wide
+	// elements three modules and a one-module gap between characters.
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"CODE39", "131131311" + "1" + "313113111" + "1" + "311131131" + "1" + "111133113" + "1" + "311133111" + "1" + "313311111" + "1" + "113311311" + "1" + "131131311"},
+		{"A-1 $/+%.", "131131311" + "1" + "311113113" + "1" + "131111313" + "1" + "311311113" + "1" + "133111311" + "1" + "131313111" + "1" + "131311131" + "1" + "131113131" + "1" + "111313131" + "1" + "331111311" + "1" + "131131311"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			widths, err := encodeCode39(tt.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := widthString(widths); got != tt.want {
+				t.Errorf("widths\n got %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeBarcodeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		encode func(string) ([]int, error)
+		data   string
+		want   string
+	}{
+		{"Code 128 non-ASCII", encodeCode128, "café", "'é'"},
+		{"Code 128 control", encodeCode128, "A\tB", `'\t'`},
+		{"Code 39 lower case", encodeCode39, "Abc", "'b'"},
+		{"Code 39 inner star", encodeCode39, "A*B", "'*'"},
+	}
+	for _, tt := range tests {
+		_, err := tt.encode(tt.data)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, want it to name %s", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package pdfgen
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Cell is a table cell with per-cell style overrides. Use it in
+// TableComponent.Data, mixed freely with plain strings.
+//
+// A cell with Span > 1 covers the following columns too. As in HTML, the
+// covered columns get no entry of their own in the row.
+//
+//	Data: [][]any{
+//	    {"Apr 3", "11:15", pdfgen.Cell{Text: "11:45", TextColor: red, Badge: "11-HR"}},
+//	}
+type Cell struct {
+	Text       string
+	Font       FontConfig // non-zero fields override the row font, e.g. FontConfig{Style: "B"}
+	TextColor  Color      // zero value → theme PrimaryText
+	Fill       Color      // zero value → row background
+	Align      string     // "L", "C", "R"; empty → ColumnDef.Align
+	Badge      string     // short pill drawn after the text, e.g. "VIOLATION"
+	BadgeColor Color      // zero value → red (#EF4444)
+	Icon       Icon       // vector symbol drawn before the text
+	IconColor  Color      // zero value → text color
+	Span       int        // number of columns covered; default 1
+	Value      any        // typed value; when Text is empty it is formatted by the column
+}
+
+// badgeRed is the default Cell.BadgeColor.
+var badgeRed = Color{R: 239, G: 68, B: 68} // #EF4444 red-500
+
+// toCell converts one TableComponent.Data or RowSource value in column col
+// to a Cell. Typed values are kept in Cell.Value and formatted by the column.
+func toCell(col ColumnDef, v any) (Cell, bool) {
+	var c Cell
+	switch x := v.(type) {
+	case nil:
+		return Cell{}, true
+	case string:
+		return Cell{Text: x}, true
+	case Cell:
+		c = x
+	case *Cell:
+		if x == nil {
+			return Cell{}, true
+		}
+		c = *x
+	default:
+		c.Value = v
+	}
+	if c.Text == "" && c.Value != nil {
+		text, ok := formatCell(col, c.Value)
+		if !ok {
+			return Cell{}, false
+		}
+		c.Text = text
+	}
+	return c, true
+}
+
+// formatValue formats the scalar values a database driver returns: strings,
+// numbers, booleans, times, UUIDs, fmt.Stringers and driver.Valuers such as
+// pgtype.Numeric. nil formats as "".
+func formatValue(v any) (string, bool) {
+	switch x := v.(type) {
+	case nil:
+		return "", true
+	case string:
+		return x, true
+	case []byte:
+		return string(x), true
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, bool:
+		return fmt.Sprint(x), true
+	case float32:
+		return strconv.FormatFloat(float64(x), 'f', -1, 32), true
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), true
+	case time.Time:
+		if x.Hour() == 0 && x.Minute() == 0 && x.Second() == 0 && x.Nanosecond() == 0 {
+			return x.Format("2006-01-02"), true
+		}
+		return x.Format("2006-01-02 15:04:05"), true
+	case [16]byte:
+		return fmt.Sprintf("%x-%x-%x-%x-%x", x[0:4], x[4:6], x[6:8], x[8:10], x[10:16]), true
+	case fmt.Stringer:
+		return x.String(), true
+	case driver.Valuer:
+		dv, err := x.Value()
+		if err != nil {
+			return "", false
+		}
+		if _, again := dv.(driver.Valuer); again {
+			return "", false
+		}
+		return formatValue(dv)
+	}
+	return "", false
+}
+
+// textCells wraps plain strings as cells.
+func textCells(row []string) []Cell {
+	cells := make([]Cell, len(row))
+	for i, s := range row {
+		cells[i].Text = s
+	}
+	return cells
+}
+
+// cellSpan returns how many of the n columns the cell starting at column i
+// covers.
+func cellSpan(c Cell, i, n int) int {
+	span := c.Span
+	if span < 1 {
+		span = 1
+	}
+	if i+span > n {
+		span = n - i
+	}
+	return span
+}
+
+// spanWidth returns the total width of columns i..i+span-1.
+func spanWidth(widths []float64, i, span int) float64 {
+	w := 0.0
+	for _, cw := range widths[i : i+span] {
+		w += cw
+	}
+	return w
+}
+
+// cellFont resolves the font of a cell from the row font, the column's Bold
+// flag and the cell's own overrides.
+func cellFont(font FontConfig, col ColumnDef, cell Cell) FontConfig {
+	if col.Bold {
+		font.Style = "B"
+	}
+	if cell.Font.Family != "" {
+		font.Family = cell.Font.Family
+	}
+	if cell.Font.Size != 0 {
+		font.Size = cell.Font.Size
+	}
+	if cell.Font.Style != "" {
+		font.Style = cell.Font.Style
+	}
+	return font
+}
+
+// badgeFont is the font of a cell badge: bold at 75% of the cell font size.
+func badgeFont(font FontConfig) FontConfig {
+	return FontConfig{Family: font.Family, Size: font.Size * 0.75, Style: "B"}
+}
+
+// Badge metrics in mm.
+const (
+	badgePadH   = 1.2
+	badgeHeight = 4.0
+)
+
+// badgeWidth returns the width of a badge, including the gap before it.
+// It leaves font as the active font.
+func badgeWidth(doc *Document, badge string, font FontConfig) float64 {
+	if badge == "" {
+		return 0
+	}
+	doc.applyFont(badgeFont(font))
+	w := doc.stringWidth(badge) + 2*badgePadH
+	doc.applyFont(font)
+	return w + doc.pdf.GetCellMargin()
+}
+
+// drawBadge draws a rounded pill with white or dark text at (x, y).
+func drawBadge(doc *Document, x, y float64, badge string, color Color, font FontConfig) {
+	if color == (Color{}) {
+		color = badgeRed
+	}
+	bf := badgeFont(font)
+	doc.applyFont(bf)
+	w := doc.stringWidth(badge) + 2*badgePadH
+	doc.applyColor(color)
+	doc.pdf.RoundedRect(x, y, w, badgeHeight, badgeHeight/2, "1234", "F")
+	doc.applyTextColor(contrastText(doc, color))
+	cm := doc.pdf.GetCellMargin()
+	doc.pdf.SetCellMargin(0)
+	doc.pdf.SetXY(x, y)
+	doc.cellFormat(w, badgeHeight, badge, "", 0, "C", false)
+	doc.pdf.SetCellMargin(cm)
+}
+
+// decorWidth returns the width the icon and badge of a cell take beside
+// its text. It leaves font as the active font.
+func decorWidth(doc *Document, cell Cell, font FontConfig) float64 {
+	return iconWidth(cell.Icon) + badgeWidth(doc, cell.Badge, font)
+}
+
+// drawCell draws the text, fill, icon and badge of one body cell whose
+// column box starts at (x, y) and is w × rowH mm.
+func (t *TableComponent) drawCell(doc *Document, x, y, w, rowH, paddingH, paddingV, lineH float64, font FontConfig, col ColumnDef, cell Cell) {
+	if cell.Fill != (Color{}) {
+		doc.applyColor(cell.Fill)
+		doc.pdf.Rect(x, y, w, rowH, "F")
+	}
+
+	align := cell.Align
+	if align == "" {
+		align = columnAlign(col)
+		if _, isNum := numberOf(cell.Value); isNum && col.Align == "" {
+			align = "R"
+		}
+	}
+	font = cellFont(font, col, cell)
+	doc.applyFont(font)
+	textColor := cell.TextColor
+	if textColor == (Color{}) {
+		textColor = doc.theme.PrimaryText
+	}
+
+	iconColor := cell.IconColor
+	if iconColor == (Color{}) {
+		iconColor = textColor
+	}
+
+	cm := doc.pdf.GetCellMargin()
+	iconW := iconWidth(cell.Icon)
+	badgeW := badgeWidth(doc, cell.Badge, font)
+	cellW := w - 2*paddingH
+	textW := cellW - iconW - badgeW
+	text := cell.Text
+
+	if col.Overflow == OverflowWrap && len(doc.splitLines(text, textW)) > 1 {
+		// Multi-line text wraps between the icon and the badge, which sit
+		// at either end of the first line.
+		if cell.Icon != IconNone {
+			drawIcon(doc, cell.Icon, x+paddingH+cm, y+paddingV+(lineH-iconSize)/2, iconColor)
+		}
+		doc.applyFont(font)
+		doc.applyTextColor(textColor)
+		doc.pdf.SetXY(x+paddingH+iconW, y+paddingV)
+		doc.multiCell(textW, lineH, text, align)
+		if cell.Badge != "" {
+			drawBadge(doc, x+paddingH+iconW+textW+cm, y+paddingV+(lineH-badgeHeight)/2, cell.Badge, cell.BadgeColor, font)
+		}
+		return
+	}
+
+	if col.Overflow == OverflowTruncate {
+		text = truncateText(doc, text, textW)
+	}
+	if cell.Badge == "" && cell.Icon == IconNone {
+		doc.applyTextColor(textColor)
+		doc.pdf.SetXY(x+paddingH, y+paddingV)
+		doc.cellFormat(cellW, rowH-2*paddingV, text, "", 0, align, false)
+		return
+	}
+
+	// Single line: icon, text and badge are aligned together as one block.
+	tw := doc.stringWidth(text) + 2*cm
+	if text == "" {
+		tw = 0
+	}
+	bx := x + paddingH
+	switch align {
+	case "C":
+		bx += (cellW - iconW - tw - badgeW) / 2
+	case "R":
+		bx += cellW - iconW - tw - badgeW
+	}
+	if bx < x+paddingH {
+		bx = x + paddingH
+	}
+	if cell.Icon != IconNone {
+		drawIcon(doc, cell.Icon, bx+cm, y+(rowH-iconSize)/2, iconColor)
+	}
+	if tw > 0 {
+		doc.applyFont(font)
+		doc.applyTextColor(textColor)
+		doc.pdf.SetXY(bx+iconW, y+paddingV)
+		doc.cellFormat(tw, rowH-2*paddingV, text, "", 0, "L", false)
+	}
+	if cell.Badge != "" {
+		drawBadge(doc, bx+iconW+tw+cm, y+(rowH-badgeHeight)/2, cell.Badge, cell.BadgeColor, font)
+	}
+}
@@ -0,0 +1,165 @@
+package pdfgen
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ChartSeries is one named data series shared by the chart components.
+type ChartSeries struct {
+	Name   string    // legend label
+	Values []float64 // one value per category
+	Color  Color     // zero value → next color of the chart palette
+}
+
+// chartPalette returns the series colors used when a series color is zero:
+// ThemeConfig.ChartPalette when set, otherwise a built-in palette whose first
+// two entries come from the theme so single-series charts match the
+// surrounding tables, followed by distinct hues for multi-series charts.
+func chartPalette(theme ThemeConfig) []Color {
+	if len(theme.ChartPalette) > 0 {
+		return theme.ChartPalette
+	}
+	return []Color{
+		theme.SectionLabelLeft,  // #334155 slate-700
+		theme.AccentColor,       // #94A3B8 slate-400
+		{R: 59, G: 130, B: 246}, // #3B82F6 blue-500
+		{R: 245, G: 158, B: 11}, // #F59E0B amber-500
+		{R: 16, G: 185, B: 129}, // #10B981 emerald-500
+		{R: 239, G: 68, B: 68},  // #EF4444 red-500
+		{R: 139, G: 92, B: 246}, // #8B5CF6 violet-500
+		{R: 6, G: 182, B: 212},  // #06B6D4 cyan-500
+	}
+}
+
+// seriesColor resolves the color of series i whose configured color is c.
+func seriesColor(doc *Document, c Color, i int) Color {
+	if c != (Color{}) {
+		return c
+	}
+	palette := chartPalette(doc.theme)
+	return palette[i%len(palette)]
+}
+
+// niceScale expands [lo, hi] to round tick boundaries and returns the
+// axis minimum, maximum and tick step for roughly ticks intervals.
+func niceScale(lo, hi float64, ticks int) (min, max, step float64) {
+	if ticks < 1 {
+		ticks = 5
+	}
+	if hi == lo {
+		if hi == 0 {
+			return 0, 1, 1.0 / float64(ticks)
+		}
+		lo, hi = math.Min(0, lo), math.Max(0, hi)
+		if hi == lo {
+			hi = lo + math.Abs(lo)
+		}
+	}
+	raw := (hi - lo) / float64(ticks)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	switch f := raw / mag; {
+	case f <= 1:
+		step = mag
+	case f <= 2:
+		step = 2 * mag
+	case f <= 2.5:
+		step = 2.5 * mag
+	case f <= 5:
+		step = 5 * mag
+	default:
+		step = 10 * mag
+	}
+	min = math.Floor(lo/step) * step
+	max = math.Ceil(hi/step) * step
+	return min, max, step
+}
+
+// formatNumber formats v with a thousands separator and the given number of
+// decimals, e.g. formatNumber(14123.5, 0) → "14,124".
+func formatNumber(v float64, decimals int) string {
+	s := strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i:]
+	}
+	sign := ""
+	if v < 0 && strings.Trim(s, "0.") != "" {
+		sign = "-"
+	}
+	return sign + groupThousands(intPart) + frac
+}
+
+// groupThousands inserts a comma between every three digits of an unsigned
+// integer, e.g. "14124" → "14,124".
+func groupThousands(digits string) string {
+	var b strings.Builder
+	for i, c := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// tickDecimals returns how many decimals are needed to print multiples of step.
+func tickDecimals(step float64) int {
+	d := 0
+	for d < 6 && math.Abs(step*math.Pow(10, float64(d))-math.Round(step*math.Pow(10, float64(d)))) > 1e-9 {
+		d++
+	}
+	return d
+}
+
+// legendItem is one swatch + label entry of a chart legend.
+type legendItem struct {
+	Label string
+	Color Color
+}
+
+// Legend metrics in mm.
+const (
+	legendSwatch = 2.8
+	legendGap    = 1.5
+	legendItemSp = 5.0
+	legendLineH  = 4.5
+)
+
+// legendLayout flows legend items left to right within width w, wrapping to
+// a new line as needed. It returns the position of each item relative to the
+// legend origin and the total legend height.
+func legendLayout(doc *Document, items []legendItem, w float64, font FontConfig) (xs, ys []float64, h float64) {
+	doc.applyFont(font)
+	cm := doc.pdf.GetCellMargin()
+	x, y := 0.0, 0.0
+	for _, it := range items {
+		itemW := legendSwatch + legendGap + doc.stringWidth(it.Label) + 2*cm
+		if x > 0 && x+itemW > w {
+			x, y = 0, y+legendLineH
+		}
+		xs = append(xs, x)
+		ys = append(ys, y)
+		x += itemW + legendItemSp
+	}
+	if len(items) > 0 {
+		h = y + legendLineH
+	}
+	return xs, ys, h
+}
+
+// drawLegend renders a legend at (x, y) and returns its height.
+func drawLegend(doc *Document, items []legendItem, x, y, w float64, font FontConfig) float64 {
+	xs, ys, h := legendLayout(doc, items, w, font)
+	doc.applyFont(font)
+	for i, it := range items {
+		ix, iy := x+xs[i], y+ys[i]
+		doc.applyColor(it.Color)
+		doc.pdf.Rect(ix, iy+(legendLineH-legendSwatch)/2, legendSwatch, legendSwatch, "F")
+		doc.applyTextColor(doc.theme.SecondaryText)
+		doc.pdf.SetXY(ix+legendSwatch+legendGap, iy)
+		doc.cellFormat(doc.stringWidth(it.Label)+2*doc.pdf.GetCellMargin(), legendLineH, it.Label, "", 0, "L", false)
+	}
+	return h
+}
@@ -3,21 +3,33 @@ package pdfgen
 import (
 	"bytes"
 	"fmt"
+	"maps"
 	"os"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/go-pdf/fpdf"
 )
 
 // DocumentConfig controls page layout and theme for a new document.
 type DocumentConfig struct {
-	PageSize     string      // "A4" or "Letter"; default "A4"
-	Orientation  string      // "portrait" or "landscape"; default "portrait"
-	MarginTop    float64     // mm; default 15
-	MarginBottom float64     // mm; default 15
-	MarginLeft   float64     // mm; default 15
-	MarginRight  float64     // mm; default 15
-	Theme        ThemeConfig // zero value → DefaultTheme()
+	PageSize     string       // "A4" or "Letter"; default "A4"
+	Orientation  string       // "portrait" or "landscape"; default "portrait"
+	MarginTop    float64      // mm; default 15
+	MarginBottom float64      // mm; default 15
+	MarginLeft   float64      // mm; default 15
+	MarginRight  float64      // mm; default 15
+	Theme        ThemeConfig  // zero value → DefaultTheme()
+	Fonts        []FontFamily // UTF-8 TrueType families, referenced by FontConfig.Family
+	MissingGlyph rune         // substituted for runes the active font lacks; default '?'
+
+	// Header and footer placeholders.
+	ReportID   string            // {report_id}
+	Date       time.Time         // {date}; zero value → time of New
+	DateLayout string            // {date} layout; default "01/02/2006"
+	Location   *time.Location    // {date} time zone; nil → Date's own
+	Vars       map[string]string // {name} → value; see also Document.SetVar
 }
 
 // Document is the root object that manages the fpdf instance and renders components.
@@ -29,9 +41,33 @@ type Document struct {
 	marginT    float64
 	marginB    float64
 	footer     *FooterComponent
+	header     *PageHeaderComponent
 	pageWidth  float64 // usable width = page width − left margin − right margin
 	err        error   // first component error encountered
 	imageCount int     // used to generate unique image names for inline images
+
+	fonts        map[string]*fontFace // registered UTF-8 faces keyed by fontKey
+	face         *fontFace            // active UTF-8 face; nil for core fonts
+	cp1252       func(string) string  // UTF-8 → cp1252 translator for core fonts
+	coreRunes    map[rune]bool        // cached cp1252 coverage per rune
+	missingGlyph rune
+
+	outline      []outlineEntry // bookmarks in document order
+	sectionDepth int            // outline level of section labels: one below the last heading
+	tocs         []tocSlot      // tables of contents filled in by finish
+	finished     bool
+
+	pages    []pageInfo        // header and footer of each page, drawn by finish
+	vars     map[string]string // placeholder variables; replaced, never mutated, by SetVar
+	date     string            // formatted {date}
+	reportID string
+
+	pageTop    float64             // Y where content starts on the current page, below the page header
+	inHeader   bool                // the page header is rendering
+	images     map[[32]byte]string // registered image data, keyed by its SHA-256
+	watermarks []*Watermark
+	sec        *section // geometry of the current page
+	pageShift  float64  // vertical shift while finish draws on a page; see setPage
 }
 
 // New creates a new Document with the given configuration.
@@ -72,24 +108,68 @@ func New(cfg DocumentConfig) *Document {
 	// Register {total} as the alias for the total page count.
 	pdf.AliasNbPages("{total}")
 
-	w, _ := pdf.GetPageSize()
+	missingGlyph := cfg.MissingGlyph
+	if missingGlyph == 0 {
+		missingGlyph = '?'
+	}
+
+	date := cfg.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	if cfg.Location != nil {
+		date = date.In(cfg.Location)
+	}
+	dateLayout := cfg.DateLayout
+	if dateLayout == "" {
+		dateLayout = "01/02/2006"
+	}
+
+	sec := newSection(pdf.GetPageSizeStr(cfg.PageSize), orientation == "L", false)
+	sec.marginT, sec.marginB = cfg.MarginTop, cfg.MarginBottom
+	sec.marginL, sec.marginR = cfg.MarginLeft, cfg.MarginRight
+
 	d := &Document{
-		pdf:       pdf,
-		theme:     theme,
-		marginL:   cfg.MarginLeft,
-		marginR:   cfg.MarginRight,
-		marginT:   cfg.MarginTop,
-		marginB:   cfg.MarginBottom,
-		pageWidth: w - cfg.MarginLeft - cfg.MarginRight,
+		pdf:          pdf,
+		theme:        theme,
+		marginL:      cfg.MarginLeft,
+		marginR:      cfg.MarginRight,
+		marginT:      cfg.MarginTop,
+		marginB:      cfg.MarginBottom,
+		pageWidth:    sec.w - cfg.MarginLeft - cfg.MarginRight,
+		sec:          sec,
+		fonts:        make(map[string]*fontFace),
+		cp1252:       pdf.UnicodeTranslatorFromDescriptor(""),
+		coreRunes:    make(map[rune]bool),
+		missingGlyph: missingGlyph,
+		images:       make(map[[32]byte]string),
+		vars:         maps.Clone(cfg.Vars),
+		date:         date.Format(dateLayout),
+		reportID:     cfg.ReportID,
+	}
+	if err := d.registerFonts(cfg.Fonts); err != nil {
+		d.err = err
 	}
 
+	// Footers are drawn by finish; closing a page records its state.
 	pdf.SetFooterFunc(func() {
-		if d.footer != nil {
-			d.footer.render(d)
-		}
+		p := d.page(pdf.PageNo())
+		p.footer, p.vars, p.closed = d.footer, d.vars, true
 	})
+	pdf.SetHeaderFuncMode(func() {
+		d.page(pdf.PageNo()).sec = d.sec
+		face := d.face
+		d.drawWatermarks(pdf.PageNo(), false)
+		if d.header != nil {
+			d.inHeader = true
+			d.header.renderPage(d)
+			d.inHeader = false
+		}
+		d.face = face
+		d.pageTop = d.currentY()
+	}, false)
 
-	pdf.AddPage()
+	// The first page is added by ensurePage, once content needs it.
 	return d
 }
 
@@ -102,6 +182,7 @@ func (d *Document) Add(components ...Component) *Document {
 		if d.err != nil {
 			return d
 		}
+		d.ensurePage()
 		if err := c.Render(d); err != nil {
 			d.err = fmt.Errorf("pdfgen: %T render: %w", c, err)
 		}
@@ -110,20 +191,101 @@ func (d *Document) Add(components ...Component) *Document {
 }
 
 // SetFooter registers the footer component that will render automatically on
-// every page. Call before adding content for best results.
+// every page. A page gets the footer registered when the page ends.
 func (d *Document) SetFooter(f *FooterComponent) *Document {
 	d.footer = f
 	return d
 }
 
+// SetPageHeader registers the header component that renders automatically at
+// the top of every page. Content on each page starts below it. Call before
+// adding content so the first page gets the header too; otherwise it starts
+// on the next page.
+func (d *Document) SetPageHeader(h *PageHeaderComponent) *Document {
+	first := d.header == nil
+	d.header = h
+	if h != nil && first && d.pdf.PageNo() == 1 && d.currentY() == d.pageTop {
+		// Nothing has flowed onto the first page yet.
+		d.pdf.SetY(d.marginT)
+		d.inHeader = true
+		h.renderPage(d)
+		d.inHeader = false
+		d.pageTop = d.currentY()
+	}
+	return d
+}
+
+// finish completes the parts of the document that depend on later pages:
+// tables of contents and header and footer text. It runs once, before
+// output.
+func (d *Document) finish() error {
+	if d.finished {
+		return nil
+	}
+	d.finished = true
+	d.ensurePage()
+	page, sec := d.pdf.PageNo(), d.sec
+	x, y := d.pdf.GetXY()
+	for i := range d.tocs {
+		s := &d.tocs[i]
+		if err := s.grow(d); err != nil {
+			d.err = err
+			return err
+		}
+	}
+	// Adding a page closes the one before; the added pages take the
+	// footer of the pages they continue.
+	for _, s := range d.tocs {
+		for _, n := range s.pages[s.reserved:] {
+			p := d.page(n)
+			p.footer, p.vars, p.closed = s.footer, s.vars, true
+		}
+	}
+	if n := d.pdf.PageCount(); n > page {
+		// fpdf writes the pages up to the current one.
+		page, sec = n, d.page(n).sec
+	}
+	numbers, totals := d.numberPages()
+	for i := range d.tocs {
+		d.tocs[i].fill(d, numbers)
+	}
+	d.drawPageText(numbers, totals)
+	if len(d.watermarks) > 0 {
+		for n := 1; n <= d.pdf.PageCount(); n++ {
+			d.setPage(n)
+			d.drawWatermarks(n, true)
+		}
+	}
+	d.putBookmarks()
+	d.endPage()
+	d.pdf.SetPage(page)
+	d.useSection(sec)
+	d.pdf.SetXY(x, y)
+	return nil
+}
+
 // Save writes the PDF to the given file path.
 func (d *Document) Save(path string) error {
 	if d.err != nil {
 		return d.err
 	}
+	if err := d.finish(); err != nil {
+		return err
+	}
 	if err := d.pdf.Error(); err != nil {
 		return fmt.Errorf("pdfgen: fpdf internal error: %w", err)
 	}
+	if d.pageOrder() != nil {
+		// Pages are reordered in memory, then written.
+		b, err := d.Bytes()
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, b, 0o644); err != nil {
+			return fmt.Errorf("pdfgen: create file %q: %w", path, err)
+		}
+		return nil
+	}
 	f, err := os.Create(path)
 	if err != nil {
 		return fmt.Errorf("pdfgen: create file %q: %w", path, err)
@@ -137,6 +299,9 @@ func (d *Document) Bytes() ([]byte, error) {
 	if d.err != nil {
 		return nil, d.err
 	}
+	if err := d.finish(); err != nil {
+		return nil, err
+	}
 	if err := d.pdf.Error(); err != nil {
 		return nil, fmt.Errorf("pdfgen: fpdf internal error: %w", err)
 	}
@@ -144,6 +309,9 @@ func (d *Document) Bytes() ([]byte, error) {
 	if err := d.pdf.Output(&buf); err != nil {
 		return nil, fmt.Errorf("pdfgen: output error: %w", err)
 	}
+	if order := d.pageOrder(); order != nil {
+		return reorderPages(buf.Bytes(), order)
+	}
 	return buf.Bytes(), nil
 }
 
@@ -164,15 +332,30 @@ func (d *Document) usableWidth() float64 {
 // newPageIfNeeded adds a new page when the remaining vertical space is less
 // than requiredHeight. Returns true if a new page was added.
 func (d *Document) newPageIfNeeded(requiredHeight float64) bool {
-	_, pageH := d.pdf.GetPageSize()
-	remaining := pageH - d.marginB - d.currentY()
-	if remaining < requiredHeight {
-		d.pdf.AddPage()
+	remaining := d.pageBottom() - d.currentY()
+	if remaining < requiredHeight && !d.inHeader {
+		d.addPage()
 		return true
 	}
 	return false
 }
 
+// pageBottom returns the lowest Y content may reach on the current page:
+// the bottom margin, raised by the extra lines of a multi-line footer.
+func (d *Document) pageBottom() float64 {
+	return d.bottomOf(d.sec, d.footer)
+}
+
+// bottomOf returns the lowest Y content may reach on a page of sec with
+// footer.
+func (d *Document) bottomOf(sec *section, footer *FooterComponent) float64 {
+	bottom := sec.h - sec.marginB
+	if footer != nil {
+		bottom -= float64(footer.lines(d)-1) * footerLineH
+	}
+	return bottom
+}
+
 // applyFont sets the active font, falling back to theme defaults for zero values.
 func (d *Document) applyFont(f FontConfig) {
 	family := f.Family
@@ -184,6 +367,7 @@ func (d *Document) applyFont(f FontConfig) {
 		size = d.theme.DefaultFont.Size
 	}
 	d.pdf.SetFont(family, f.Style, size)
+	d.face = d.fonts[fontKey(family, f.Style)]
 }
 
 // applyColor sets both the draw color (lines, rect borders) and fill color.
@@ -196,3 +380,65 @@ func (d *Document) applyColor(c Color) {
 func (d *Document) applyTextColor(c Color) {
 	d.pdf.SetTextColor(c.R, c.G, c.B)
 }
+
+// stringWidth returns the width of UTF-8 s in mm under the active font.
+func (d *Document) stringWidth(s string) float64 {
+	return d.pdf.GetStringWidth(d.text(s))
+}
+
+// cellFormat is fpdf CellFormat with s translated for the active font.
+func (d *Document) cellFormat(w, h float64, s, borderStr string, ln int, alignStr string, fill bool) {
+	d.pdf.CellFormat(w, h, d.text(s), borderStr, ln, alignStr, fill, 0, "")
+}
+
+// multiCell draws s wrapped to width w starting at the current position, one
+// line of height h per wrapped line. Unlike fpdf MultiCell it measures UTF-8
+// text correctly for both core and TrueType fonts.
+func (d *Document) multiCell(w, h float64, s, alignStr string) {
+	x := d.pdf.GetX()
+	for _, line := range d.splitLines(s, w) {
+		d.pdf.SetX(x)
+		d.cellFormat(w, h, line, "", 2, alignStr, false)
+	}
+}
+
+// splitLines wraps UTF-8 s into lines that fit within a cell of width w mm
+// under the active font. Explicit newlines are honoured and words wider than
+// the cell are broken.
+func (d *Document) splitLines(s string, w float64) []string {
+	s = strings.TrimRight(strings.ReplaceAll(s, "\r", ""), "\n")
+	if s == "" {
+		return nil
+	}
+	maxW := w - 2*d.pdf.GetCellMargin()
+
+	var lines []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for i, word := range strings.Split(para, " ") {
+			candidate := word
+			if i > 0 {
+				candidate = line + " " + word
+			}
+			if d.stringWidth(candidate) <= maxW {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			for d.stringWidth(word) > maxW && utf8.RuneCountInString(word) > 1 {
+				runes := []rune(word)
+				n := len(runes) - 1
+				for n > 1 && d.stringWidth(string(runes[:n])) > maxW {
+					n--
+				}
+				lines = append(lines, string(runes[:n]))
+				word = string(runes[n:])
+			}
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
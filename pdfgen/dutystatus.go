@@ -0,0 +1,330 @@
+package pdfgen
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// DutyStatus is one of the four rows of the FMCSA record-of-duty-status grid.
+type DutyStatus int
+
+const (
+	// DutyOffDuty is row 1, "Off Duty".
+	DutyOffDuty DutyStatus = iota
+	// DutySleeperBerth is row 2, "Sleeper Berth".
+	DutySleeperBerth
+	// DutyDriving is row 3, "Driving".
+	DutyDriving
+	// DutyOnDuty is row 4, "On Duty (Not Driving)".
+	DutyOnDuty
+)
+
+// DutyStatusChange marks the moment the driver entered Status.
+type DutyStatusChange struct {
+	At     time.Time
+	Status DutyStatus
+}
+
+// DutyRemark is a numbered marker drawn under the grid at At, with its text
+// listed below the grid (location, inspection, etc.).
+type DutyRemark struct {
+	At   time.Time
+	Text string
+}
+
+// DutyStatusGridComponent draws the standard 24-hour record-of-duty-status
+// grid: four status rows, hour columns with 15-minute ticks, the duty-status
+// step line, per-row hour totals on the right and remark markers underneath.
+//
+// Time axis: Day is the start of the log day; its Location sets the time zone.
+// The status in effect before the first change inside the day is taken from
+// the last change at or before Day, or Off Duty when there is none.
+// The whole grid is kept together on one page.
+type DutyStatusGridComponent struct {
+	Day         time.Time          // start of the 24-hour log day (midnight in the carrier's home terminal zone)
+	Changes     []DutyStatusChange // status changes; need not be sorted
+	Remarks     []DutyRemark       // optional remark markers
+	End         time.Time          // zero = end of day; set for the current day so the line stops at "now"
+	RowLabels   [4]string          // zero values → "Off Duty", "Sleeper Berth", "Driving", "On Duty (Not Driving)"
+	LabelWidth  float64            // mm; default 30
+	TotalWidth  float64            // mm; default 16
+	RowHeight   float64            // mm; default 7
+	LineWidth   float64            // step line width in mm; default 0.6
+	LineColor   Color              // step line color; zero value → theme PrimaryText
+	Font        FontConfig         // zero value → theme default at 7pt
+	RemarksFont FontConfig         // zero value → Font
+}
+
+// Render draws the grid and advances the Y cursor.
+func (g *DutyStatusGridComponent) Render(doc *Document) error {
+	if g.Day.IsZero() {
+		return fmt.Errorf("pdfgen: DutyStatusGridComponent requires Day")
+	}
+	dayStart := g.Day
+	dayEnd := dayStart.Add(24 * time.Hour)
+	end := dayEnd
+	if !g.End.IsZero() && g.End.Before(dayEnd) {
+		end = g.End
+	}
+
+	labelW := g.LabelWidth
+	if labelW == 0 {
+		labelW = 30
+	}
+	totalW := g.TotalWidth
+	if totalW == 0 {
+		totalW = 16
+	}
+	rowH := g.RowHeight
+	if rowH == 0 {
+		rowH = 7
+	}
+	lineW := g.LineWidth
+	if lineW == 0 {
+		lineW = 0.6
+	}
+	lineColor := g.LineColor
+	if lineColor.R == 0 && lineColor.G == 0 && lineColor.B == 0 {
+		lineColor = doc.theme.PrimaryText
+	}
+	font := g.Font
+	if font.Family == "" {
+		font = doc.theme.DefaultFont
+		font.Size = 7
+	}
+	remarksFont := g.RemarksFont
+	if remarksFont.Family == "" {
+		remarksFont = font
+	}
+	labels := g.RowLabels
+	defaults := [4]string{"Off Duty", "Sleeper Berth", "Driving", "On Duty (Not Driving)"}
+	for i := range labels {
+		if labels[i] == "" {
+			labels[i] = defaults[i]
+		}
+	}
+
+	const (
+		hourLabelH  = 4.5
+		remarkLineH = 3.8
+	)
+	gridW := doc.usableWidth() - labelW - totalW
+	if gridW <= 0 {
+		return fmt.Errorf("pdfgen: DutyStatusGridComponent LabelWidth + TotalWidth exceed usable width")
+	}
+	gridH := 4 * rowH
+
+	remarks := append([]DutyRemark(nil), g.Remarks...)
+	sort.SliceStable(remarks, func(i, j int) bool { return remarks[i].At.Before(remarks[j].At) })
+	for _, c := range g.Changes {
+		if c.Status < DutyOffDuty || c.Status > DutyOnDuty {
+			return fmt.Errorf("pdfgen: DutyStatusGridComponent unknown status %d at %s", c.Status, c.At.Format(time.RFC3339))
+		}
+	}
+
+	// Below the grid: one band for remark markers and the grand total, then
+	// one line per remark.
+	belowH := hourLabelH
+	if len(remarks) > 0 {
+		belowH += float64(len(remarks))*remarkLineH + 1
+	}
+	totalH := hourLabelH + gridH + belowH
+
+	doc.newPageIfNeeded(totalH)
+
+	startX := doc.marginL
+	startY := doc.currentY()
+	gridX := startX + labelW
+	gridY := startY + hourLabelH
+	xAt := func(t time.Time) float64 {
+		m := t.Sub(dayStart).Minutes()
+		if m < 0 {
+			m = 0
+		}
+		if m > 1440 {
+			m = 1440
+		}
+		return gridX + gridW*m/1440
+	}
+
+	// ── Hour labels ──────────────────────────────────────────────────────────
+	doc.applyFont(font)
+	doc.applyTextColor(doc.theme.SecondaryText)
+	hourW := gridW / 24
+	for h := 0; h <= 24; h++ {
+		label := fmt.Sprintf("%d", h%12)
+		switch h {
+		case 0, 24:
+			label = "M"
+		case 12:
+			label = "N"
+		}
+		doc.pdf.SetXY(gridX+float64(h)*hourW-hourW/2, startY)
+		doc.cellFormat(hourW, hourLabelH, label, "", 0, "C", false)
+	}
+	doc.pdf.SetXY(gridX+gridW, startY)
+	doc.cellFormat(totalW, hourLabelH, "Total", "", 0, "C", false)
+
+	// ── Grid ─────────────────────────────────────────────────────────────────
+	doc.applyColor(doc.theme.TableBorderColor)
+	doc.pdf.Rect(startX, gridY, labelW+gridW+totalW, gridH, "D")
+	for r := 1; r < 4; r++ {
+		y := gridY + float64(r)*rowH
+		doc.pdf.Line(startX, y, startX+labelW+gridW+totalW, y)
+	}
+	doc.pdf.Line(gridX, gridY, gridX, gridY+gridH)
+	doc.pdf.Line(gridX+gridW, gridY, gridX+gridW, gridY+gridH)
+	for h := 0; h < 24; h++ {
+		x := gridX + float64(h)*hourW
+		if h > 0 {
+			doc.pdf.Line(x, gridY, x, gridY+gridH)
+		}
+		// 15-minute ticks hang from the top of each row; the half hour is longer.
+		for q := 1; q < 4; q++ {
+			tickX := x + float64(q)*hourW/4
+			tickH := rowH / 4
+			if q == 2 {
+				tickH = rowH / 2
+			}
+			for r := 0; r < 4; r++ {
+				y := gridY + float64(r)*rowH
+				doc.pdf.Line(tickX, y, tickX, y+tickH)
+			}
+		}
+	}
+
+	// ── Row labels and totals ────────────────────────────────────────────────
+	totals := g.totals(dayStart, end)
+	var sum time.Duration
+	for r := 0; r < 4; r++ {
+		y := gridY + float64(r)*rowH
+		doc.applyFont(font)
+		doc.applyTextColor(doc.theme.PrimaryText)
+		doc.pdf.SetXY(startX+1, y)
+		doc.cellFormat(labelW-2, rowH, truncateText(doc, labels[r], labelW-2), "", 0, "L", false)
+
+		boldFont := font
+		boldFont.Style = "B"
+		doc.applyFont(boldFont)
+		doc.pdf.SetXY(gridX+gridW, y)
+		doc.cellFormat(totalW, rowH, formatHoursMinutes(totals[r]), "", 0, "C", false)
+		sum += totals[r]
+	}
+	doc.applyFont(font)
+	doc.applyTextColor(doc.theme.SecondaryText)
+	doc.pdf.SetXY(gridX+gridW, gridY+gridH)
+	doc.cellFormat(totalW, hourLabelH, "= "+formatHoursMinutes(sum), "", 0, "C", false)
+
+	// ── Step line ────────────────────────────────────────────────────────────
+	savedLineW := doc.pdf.GetLineWidth()
+	doc.pdf.SetLineWidth(lineW)
+	doc.applyColor(lineColor)
+	rowMid := func(s DutyStatus) float64 {
+		return gridY + float64(s)*rowH + rowH/2
+	}
+	for i, seg := range g.segments(dayStart, end) {
+		x1, x2 := xAt(seg.from), xAt(seg.to)
+		y := rowMid(seg.status)
+		doc.pdf.Line(x1, y, x2, y)
+		if i > 0 {
+			doc.pdf.Line(x1, rowMid(seg.prev), x1, y)
+		}
+	}
+	doc.pdf.SetLineWidth(savedLineW)
+
+	// ── Remarks ──────────────────────────────────────────────────────────────
+	if len(remarks) > 0 {
+		markerY := gridY + gridH
+		doc.applyFont(remarksFont)
+		for i, rm := range remarks {
+			x := xAt(rm.At)
+			doc.applyColor(doc.theme.SecondaryText)
+			doc.pdf.Line(x, markerY, x, markerY+hourLabelH/2)
+			doc.applyTextColor(doc.theme.SecondaryText)
+			doc.pdf.SetXY(x-3, markerY+hourLabelH/2)
+			doc.cellFormat(6, hourLabelH/2, fmt.Sprintf("%d", i+1), "", 0, "C", false)
+
+			y := markerY + hourLabelH + float64(i)*remarkLineH
+			doc.applyTextColor(doc.theme.PrimaryText)
+			doc.pdf.SetXY(startX, y)
+			prefix := fmt.Sprintf("%d.  %s  ", i+1, rm.At.In(dayStart.Location()).Format("15:04"))
+			prefixW := doc.stringWidth(prefix) + 1
+			doc.cellFormat(prefixW, remarkLineH, prefix, "", 0, "L", false)
+			textW := doc.usableWidth() - prefixW
+			doc.cellFormat(textW, remarkLineH, truncateText(doc, rm.Text, textW), "", 0, "L", false)
+		}
+	}
+
+	doc.setY(startY + totalH + 2)
+	return nil
+}
+
+// dutySegment is a span of time spent in one status.
+type dutySegment struct {
+	from, to time.Time
+	status   DutyStatus
+	prev     DutyStatus // status of the preceding segment
+}
+
+// segments returns the contiguous status spans covering [from, to).
+func (g *DutyStatusGridComponent) segments(from, to time.Time) []dutySegment {
+	changes := append([]DutyStatusChange(nil), g.Changes...)
+	sort.SliceStable(changes, func(i, j int) bool { return changes[i].At.Before(changes[j].At) })
+	// Of several changes at one instant, the last in input order wins.
+	n := 0
+	for _, c := range changes {
+		if n > 0 && c.At.Equal(changes[n-1].At) {
+			n--
+		}
+		changes[n] = c
+		n++
+	}
+	changes = changes[:n]
+
+	current := DutyOffDuty
+	for _, c := range changes {
+		if c.At.After(from) {
+			break
+		}
+		current = c.Status
+	}
+
+	var segs []dutySegment
+	cursor := from
+	prev := current
+	for _, c := range changes {
+		if !c.At.After(cursor) {
+			continue
+		}
+		if !c.At.Before(to) {
+			break
+		}
+		if c.Status == current {
+			continue
+		}
+		segs = append(segs, dutySegment{from: cursor, to: c.At, status: current, prev: prev})
+		prev, current, cursor = current, c.Status, c.At
+	}
+	if cursor.Before(to) {
+		segs = append(segs, dutySegment{from: cursor, to: to, status: current, prev: prev})
+	}
+	return segs
+}
+
+// totals returns the time spent in each status within [from, to).
+func (g *DutyStatusGridComponent) totals(from, to time.Time) [4]time.Duration {
+	var t [4]time.Duration
+	for _, seg := range g.segments(from, to) {
+		if seg.status >= DutyOffDuty && seg.status <= DutyOnDuty {
+			t[seg.status] += seg.to.Sub(seg.from)
+		}
+	}
+	return t
+}
+
+// formatHoursMinutes formats d as "HH:MM", rounding to the nearest minute.
+func formatHoursMinutes(d time.Duration) string {
+	m := int(d.Round(time.Minute).Minutes())
+	return fmt.Sprintf("%02d:%02d", m/60, m%60)
+}
@@ -0,0 +1,331 @@
+package pdfgen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// FontFamily is a TrueType family embedded into the document as UTF-8 fonts.
+// Reference it from FontConfig.Family (or ThemeConfig.DefaultFont.Family) by Name.
+//
+// Only Regular is required. A missing Bold, Italic or BoldItalic face falls
+// back to the closest registered face so FontConfig.Style never fails.
+type FontFamily struct {
+	Name       string // family name used in FontConfig.Family, e.g. "DejaVu"
+	Regular    []byte // TTF bytes; required
+	Bold       []byte // TTF bytes; optional
+	Italic     []byte // TTF bytes; optional
+	BoldItalic []byte // TTF bytes; optional
+}
+
+// FontFamilyFromFS reads the TTF faces of a family from fsys, typically an
+// embed.FS. Empty paths are skipped; regular is required.
+func FontFamilyFromFS(fsys fs.FS, name, regular, bold, italic, boldItalic string) (FontFamily, error) {
+	ff := FontFamily{Name: name}
+	if regular == "" {
+		return ff, fmt.Errorf("pdfgen: font family %q requires a regular face", name)
+	}
+	faces := []struct {
+		path string
+		dst  *[]byte
+	}{
+		{regular, &ff.Regular},
+		{bold, &ff.Bold},
+		{italic, &ff.Italic},
+		{boldItalic, &ff.BoldItalic},
+	}
+	for _, f := range faces {
+		if f.path == "" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, f.path)
+		if err != nil {
+			return ff, fmt.Errorf("pdfgen: read font %q: %w", f.path, err)
+		}
+		*f.dst = data
+	}
+	return ff, nil
+}
+
+// fontFace is one registered style of a UTF-8 family and the set of runes its
+// cmap can render.
+type fontFace struct {
+	ranges []runeRange
+}
+
+type runeRange struct {
+	lo, hi rune
+}
+
+// covers reports whether the face has a glyph for r.
+func (f *fontFace) covers(r rune) bool {
+	i := sort.Search(len(f.ranges), func(i int) bool { return f.ranges[i].hi >= r })
+	return i < len(f.ranges) && f.ranges[i].lo <= r
+}
+
+// registerFonts embeds every configured family into the fpdf instance.
+func (d *Document) registerFonts(families []FontFamily) error {
+	for _, ff := range families {
+		if ff.Name == "" {
+			return fmt.Errorf("pdfgen: font family requires a Name")
+		}
+		if len(ff.Regular) == 0 {
+			return fmt.Errorf("pdfgen: font family %q requires a regular face", ff.Name)
+		}
+		// Fall back to the nearest available face for missing styles.
+		bold := firstNonEmpty(ff.Bold, ff.Regular)
+		italic := firstNonEmpty(ff.Italic, ff.Regular)
+		boldItalic := firstNonEmpty(ff.BoldItalic, ff.Bold, ff.Italic, ff.Regular)
+
+		for _, face := range []struct {
+			style string
+			data  []byte
+		}{
+			{"", ff.Regular},
+			{"B", bold},
+			{"I", italic},
+			{"BI", boldItalic},
+		} {
+			ranges, err := parseCmap(face.data)
+			if err != nil {
+				return fmt.Errorf("pdfgen: font family %q style %q: %w", ff.Name, face.style, err)
+			}
+			d.pdf.AddUTF8FontFromBytes(ff.Name, face.style, face.data)
+			d.fonts[fontKey(ff.Name, face.style)] = &fontFace{ranges: ranges}
+		}
+		if err := d.pdf.Error(); err != nil {
+			return fmt.Errorf("pdfgen: font family %q: %w", ff.Name, err)
+		}
+	}
+	return nil
+}
+
+// fontKey normalises a family and style into the key used by Document.fonts.
+func fontKey(family, style string) string {
+	style = strings.ToUpper(style)
+	norm := ""
+	if strings.Contains(style, "B") {
+		norm += "B"
+	}
+	if strings.Contains(style, "I") {
+		norm += "I"
+	}
+	return strings.ToLower(family) + "/" + norm
+}
+
+func firstNonEmpty(faces ...[]byte) []byte {
+	for _, f := range faces {
+		if len(f) > 0 {
+			return f
+		}
+	}
+	return nil
+}
+
+// text converts UTF-8 s into the encoding expected by the active font.
+// UTF-8 TrueType fonts receive s unchanged; core fonts receive cp1252.
+// Runes the active font cannot render are replaced by the missing glyph.
+func (d *Document) text(s string) string {
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return s
+	}
+
+	if d.face != nil {
+		if d.faceCovers(s) {
+			return s
+		}
+		var b strings.Builder
+		for _, r := range s {
+			if r < utf8.RuneSelf || d.face.covers(r) {
+				b.WriteRune(r)
+			} else {
+				b.WriteString(d.missingGlyphFor(d.face.covers))
+			}
+		}
+		return b.String()
+	}
+
+	var b strings.Builder
+	for _, r := range s {
+		if r < utf8.RuneSelf || d.coreCovers(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteString(d.missingGlyphFor(d.coreCovers))
+		}
+	}
+	return d.cp1252(b.String())
+}
+
+func (d *Document) faceCovers(s string) bool {
+	for _, r := range s {
+		if r >= utf8.RuneSelf && !d.face.covers(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// coreCovers reports whether r has a cp1252 code point.
+func (d *Document) coreCovers(r rune) bool {
+	if ok, seen := d.coreRunes[r]; seen {
+		return ok
+	}
+	// The fpdf translator emits "." for runes outside the code page.
+	ok := r == '.' || d.cp1252(string(r)) != "."
+	d.coreRunes[r] = ok
+	return ok
+}
+
+// missingGlyphFor returns the configured missing glyph when the font can
+// render it, or "?" otherwise.
+func (d *Document) missingGlyphFor(covers func(rune) bool) string {
+	if d.missingGlyph < utf8.RuneSelf || covers(d.missingGlyph) {
+		return string(d.missingGlyph)
+	}
+	return "?"
+}
+
+// parseCmap returns the sorted rune ranges mapped to a non-zero glyph by the
+// font's Unicode cmap subtable (format 12 preferred, then format 4).
+func parseCmap(data []byte) ([]runeRange, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("truncated font data")
+	}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	cmapOff := -1
+	for i := 0; i < numTables; i++ {
+		rec := 12 + 16*i
+		if rec+16 > len(data) {
+			return nil, fmt.Errorf("truncated table directory")
+		}
+		if string(data[rec:rec+4]) == "cmap" {
+			cmapOff = int(binary.BigEndian.Uint32(data[rec+8:]))
+			break
+		}
+	}
+	if cmapOff < 0 || cmapOff+4 > len(data) {
+		return nil, fmt.Errorf("font has no cmap table")
+	}
+
+	cmap := data[cmapOff:]
+	numSub := int(binary.BigEndian.Uint16(cmap[2:]))
+	best, bestRank := -1, 0
+	for i := 0; i < numSub; i++ {
+		rec := 4 + 8*i
+		if rec+8 > len(cmap) {
+			return nil, fmt.Errorf("truncated cmap")
+		}
+		platform := binary.BigEndian.Uint16(cmap[rec:])
+		encoding := binary.BigEndian.Uint16(cmap[rec+2:])
+		off := int(binary.BigEndian.Uint32(cmap[rec+4:]))
+		if off+2 > len(cmap) {
+			continue
+		}
+		format := binary.BigEndian.Uint16(cmap[off:])
+		rank := 0
+		switch {
+		case format == 12 && (platform == 3 && encoding == 10 || platform == 0):
+			rank = 2
+		case format == 4 && (platform == 3 && encoding == 1 || platform == 0):
+			rank = 1
+		}
+		if rank > bestRank {
+			best, bestRank = off, rank
+		}
+	}
+	if best < 0 {
+		return nil, fmt.Errorf("font has no Unicode cmap subtable")
+	}
+
+	sub := cmap[best:]
+	if bestRank == 2 {
+		return parseCmap12(sub)
+	}
+	return parseCmap4(sub)
+}
+
+func parseCmap12(sub []byte) ([]runeRange, error) {
+	if len(sub) < 16 {
+		return nil, fmt.Errorf("truncated cmap format 12")
+	}
+	n := int(binary.BigEndian.Uint32(sub[12:]))
+	if 16+12*n > len(sub) {
+		return nil, fmt.Errorf("truncated cmap format 12")
+	}
+	ranges := make([]runeRange, 0, n)
+	for i := 0; i < n; i++ {
+		g := sub[16+12*i:]
+		lo := rune(binary.BigEndian.Uint32(g))
+		hi := rune(binary.BigEndian.Uint32(g[4:]))
+		if binary.BigEndian.Uint32(g[8:]) == 0 {
+			lo++ // the first code point maps to .notdef
+		}
+		if lo <= hi {
+			ranges = appendRange(ranges, lo, hi)
+		}
+	}
+	return ranges, nil
+}
+
+func parseCmap4(sub []byte) ([]runeRange, error) {
+	if len(sub) < 14 {
+		return nil, fmt.Errorf("truncated cmap format 4")
+	}
+	segX2 := int(binary.BigEndian.Uint16(sub[6:]))
+	endOff := 14
+	startOff := endOff + segX2 + 2
+	deltaOff := startOff + segX2
+	rangeOff := deltaOff + segX2
+	if rangeOff+segX2 > len(sub) {
+		return nil, fmt.Errorf("truncated cmap format 4")
+	}
+
+	var ranges []runeRange
+	for s := 0; s < segX2; s += 2 {
+		end := int(binary.BigEndian.Uint16(sub[endOff+s:]))
+		start := int(binary.BigEndian.Uint16(sub[startOff+s:]))
+		delta := int(binary.BigEndian.Uint16(sub[deltaOff+s:]))
+		ro := int(binary.BigEndian.Uint16(sub[rangeOff+s:]))
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			glyph := 0
+			if ro == 0 {
+				glyph = (c + delta) & 0xFFFF
+			} else {
+				addr := rangeOff + s + ro + 2*(c-start)
+				if addr+2 > len(sub) {
+					break
+				}
+				glyph = int(binary.BigEndian.Uint16(sub[addr:]))
+				if glyph != 0 {
+					glyph = (glyph + delta) & 0xFFFF
+				}
+			}
+			if glyph != 0 {
+				ranges = appendRange(ranges, rune(c), rune(c))
+			}
+		}
+	}
+	return ranges, nil
+}
+
+// appendRange appends [lo, hi], merging it into the last range when adjacent.
+func appendRange(ranges []runeRange, lo, hi rune) []runeRange {
+	if n := len(ranges); n > 0 && ranges[n-1].hi+1 >= lo {
+		if hi > ranges[n-1].hi {
+			ranges[n-1].hi = hi
+		}
+		return ranges
+	}
+	return append(ranges, runeRange{lo: lo, hi: hi})
+}
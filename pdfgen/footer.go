@@ -1,38 +1,86 @@
 package pdfgen
 
-import (
-	"fmt"
-	"strings"
-)
+import "strings"
 
-// FooterComponent renders a one-line footer on every page.
+// FooterComponent renders a footer on every page.
 // Register it via doc.SetFooter() — do NOT pass to doc.Add().
 //
-// Placeholders in text fields:
-//   {page}  → current page number
-//   {total} → total page count
+// Placeholders work in all three text fields:
+//   {page}      → current page number
+//   {total}     → total page count
+//   {date}      → DocumentConfig.Date in DateLayout and Location
+//   {report_id} → DocumentConfig.ReportID
+//   {name}      → DocumentConfig.Vars or doc.SetVar
+//
+// A "\n" in a text field starts another line; each line is 5mm high. Extra
+// lines stack upward from the bottom margin, and content stops above the
+// tallest variant of the registered footer.
 type FooterComponent struct {
-	LeftText   string     // static text, left-aligned
-	CenterText string     // center-aligned; supports {page} and {total}
-	RightText  string     // static text, right-aligned
-	ShowBorder bool       // draw a thin top border line
-	Font       FontConfig // zero value → theme default at 8pt
-	TextColor  Color      // zero value → theme SecondaryText
+	LeftText   string           // left-aligned
+	CenterText string           // center-aligned
+	RightText  string           // right-aligned
+	ShowBorder bool             // draw a thin top border line
+	Font       FontConfig       // zero value → theme default at 8pt
+	TextColor  Color            // zero value → theme SecondaryText
+	FirstPage  *FooterComponent // footer of page 1; nil → this footer
+	EvenPages  *FooterComponent // footer of even pages; nil → this footer
 }
 
 // Render implements Component so FooterComponent can also be used standalone,
-// but it is normally called automatically via the fpdf footer callback.
+// but it is normally drawn automatically on every page. Standalone, {total}
+// is replaced at output time.
 func (f *FooterComponent) Render(doc *Document) error {
-	f.render(doc)
+	f.draw(doc, doc.pdf.PageNo(), 0, doc.vars)
 	return nil
 }
 
-// render is called by the fpdf footer callback on every page.
-func (f *FooterComponent) render(doc *Document) {
+// forPage returns the footer variant of page n.
+func (f *FooterComponent) forPage(n int) *FooterComponent {
+	if n == 1 && f.FirstPage != nil {
+		return f.FirstPage
+	}
+	if n%2 == 0 && f.EvenPages != nil {
+		return f.EvenPages
+	}
+	return f
+}
+
+// footerLineH is the height of a footer text line in mm.
+const footerLineH = 5.0
+
+// lines returns the most text lines of f and its variants.
+func (f *FooterComponent) lines(doc *Document) int {
+	n := 1
+	for _, v := range []*FooterComponent{f, f.FirstPage, f.EvenPages} {
+		if v == nil {
+			continue
+		}
+		for _, text := range []string{v.LeftText, v.CenterText, v.RightText} {
+			n = max(n, strings.Count(doc.expandPlaceholders(text, 0, 0, doc.vars), "\n")+1)
+		}
+	}
+	return n
+}
+
+// draw renders the footer of page n at the bottom margin.
+func (f *FooterComponent) draw(doc *Document, page, total int, vars map[string]string) {
 	pdf := doc.pdf
 
-	// Position at the bottom margin area.
-	pdf.SetY(-doc.marginB)
+	slots := []struct {
+		text, align string
+	}{{f.LeftText, "L"}, {f.CenterText, "C"}, {f.RightText, "R"}}
+	lines := 1
+	for i := range slots {
+		slots[i].text = doc.expandPlaceholders(slots[i].text, page, total, vars)
+		lines = max(lines, strings.Count(slots[i].text, "\n")+1)
+	}
+
+	w := doc.pageWidth
+	h := footerLineH
+
+	// Position at the bottom margin area, raised so the last line still
+	// starts there.
+	pdf.SetY(doc.sec.h - doc.marginB - float64(lines-1)*h)
 
 	font := f.Font
 	if font.Family == "" {
@@ -55,16 +103,12 @@ func (f *FooterComponent) render(doc *Document) {
 		doc.applyTextColor(color)
 	}
 
-	// Replace {page} with the current page number.
-	// {total} is left as-is; fpdf replaces it at output time via AliasNbPages.
-	pageNum := fmt.Sprintf("%d", pdf.PageNo())
-	center := strings.ReplaceAll(f.CenterText, "{page}", pageNum)
-
-	w := doc.pageWidth
-	h := 5.0
+	y := pdf.GetY()
 
-	pdf.SetX(doc.marginL)
-	pdf.CellFormat(w/3, h, f.LeftText, "", 0, "L", false, 0, "")
-	pdf.CellFormat(w/3, h, center, "", 0, "C", false, 0, "")
-	pdf.CellFormat(w/3, h, f.RightText, "", 0, "R", false, 0, "")
+	for i, slot := range slots {
+		for j, line := range strings.Split(slot.text, "\n") {
+			pdf.SetXY(doc.marginL+float64(i)*w/3, y+float64(j)*h)
+			doc.cellFormat(w/3, h, line, "", 0, slot.align, false)
+		}
+	}
 }
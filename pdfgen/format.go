@@ -0,0 +1,273 @@
+package pdfgen
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FormatKind selects how a ValueFormat renders typed cell values.
+type FormatKind int
+
+const (
+	// FormatNone uses the default formatting of TableComponent.Data.
+	FormatNone FormatKind = iota
+	// FormatNumber prints numbers with thousands separators.
+	FormatNumber
+	// FormatCurrency prints numbers as money, e.g. "$1,204.50".
+	FormatCurrency
+	// FormatDistance converts and prints distances, e.g. "1,240 mi".
+	FormatDistance
+	// FormatTime prints time.Time values in a layout and time zone.
+	FormatTime
+	// FormatDuration prints time.Duration values as HH:MM.
+	FormatDuration
+)
+
+// DistanceUnit is a unit of FormatDistance.
+type DistanceUnit int
+
+const (
+	// Meters is the SI meter.
+	Meters DistanceUnit = iota
+	// Kilometers is 1,000 meters.
+	Kilometers
+	// Miles is the international mile of 1,609.344 meters.
+	Miles
+)
+
+// meters returns the length of the unit in meters.
+func (u DistanceUnit) meters() float64 {
+	switch u {
+	case Kilometers:
+		return 1000
+	case Miles:
+		return 1609.344
+	}
+	return 1
+}
+
+func (u DistanceUnit) suffix() string {
+	switch u {
+	case Kilometers:
+		return " km"
+	case Miles:
+		return " mi"
+	}
+	return " m"
+}
+
+// ValueFormat formats the typed values of a column: ints, floats, decimals
+// (any fmt.Stringer or driver.Valuer such as pgtype.Numeric), time.Time and
+// time.Duration. Build one with NumberFormat, CurrencyFormat,
+// DistanceFormat, TimeFormat or DurationFormat.
+type ValueFormat struct {
+	Kind     FormatKind
+	Decimals int            // digits after the decimal point
+	Symbol   string         // FormatCurrency: prefix, e.g. "$"
+	From, To DistanceUnit   // FormatDistance: unit of the values and unit printed
+	Layout   string         // FormatTime: time layout; default "01/02/2006 15:04:05"
+	Location *time.Location // FormatTime: nil → the value's own location
+}
+
+// NumberFormat prints numbers with thousands separators and the given
+// number of decimals, e.g. NumberFormat(1) → "14,123.5".
+func NumberFormat(decimals int) ValueFormat {
+	return ValueFormat{Kind: FormatNumber, Decimals: decimals}
+}
+
+// CurrencyFormat prints numbers as money, e.g. CurrencyFormat("$", 2) →
+// "$1,204.50" and "-$12.50".
+func CurrencyFormat(symbol string, decimals int) ValueFormat {
+	return ValueFormat{Kind: FormatCurrency, Symbol: symbol, Decimals: decimals}
+}
+
+// DistanceFormat converts distances stored in from to to, e.g.
+// DistanceFormat(Meters, Miles, 0) → "1,240 mi".
+func DistanceFormat(from, to DistanceUnit, decimals int) ValueFormat {
+	return ValueFormat{Kind: FormatDistance, From: from, To: to, Decimals: decimals}
+}
+
+// TimeFormat prints times in layout, converted to loc when it is not nil.
+func TimeFormat(layout string, loc *time.Location) ValueFormat {
+	return ValueFormat{Kind: FormatTime, Layout: layout, Location: loc}
+}
+
+// DurationFormat prints durations as HH:MM, e.g. "11:45" or "34:05".
+// Numeric values are taken as seconds.
+func DurationFormat() ValueFormat {
+	return ValueFormat{Kind: FormatDuration}
+}
+
+// numeric reports whether the format prints numbers, which right-align by
+// default.
+func (f ValueFormat) numeric() bool {
+	switch f.Kind {
+	case FormatNumber, FormatCurrency, FormatDistance, FormatDuration:
+		return true
+	}
+	return false
+}
+
+// format renders v. ok is false when v is not of a type the format accepts.
+func (f ValueFormat) format(v any) (string, bool) {
+	if v == nil {
+		return "", true
+	}
+	switch f.Kind {
+	case FormatNumber, FormatCurrency, FormatDistance:
+		n, ok := numberOf(v)
+		if !ok {
+			return "", false
+		}
+		switch f.Kind {
+		case FormatCurrency:
+			s := f.Symbol + formatNumber(math.Abs(n), f.Decimals)
+			if n < 0 && strings.Trim(formatNumber(n, f.Decimals), "-0.,") != "" {
+				s = "-" + s
+			}
+			return s, true
+		case FormatDistance:
+			return formatNumber(n*f.From.meters()/f.To.meters(), f.Decimals) + f.To.suffix(), true
+		}
+		return formatNumber(n, f.Decimals), true
+	case FormatTime:
+		t, ok := timeOf(v)
+		if !ok {
+			return "", false
+		}
+		if f.Location != nil {
+			t = t.In(f.Location)
+		}
+		layout := f.Layout
+		if layout == "" {
+			layout = "01/02/2006 15:04:05"
+		}
+		return t.Format(layout), true
+	case FormatDuration:
+		d, ok := durationOf(v)
+		if !ok {
+			return "", false
+		}
+		return formatDuration(d), true
+	}
+	return formatValue(v)
+}
+
+// formatDuration prints d as hours and minutes, rounded to the minute.
+func formatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	mins := int64(d.Round(time.Minute) / time.Minute)
+	return fmt.Sprintf("%s%d:%02d", sign, mins/60, mins%60)
+}
+
+// numberOf returns the numeric value of a typed cell value. Durations count
+// in nanoseconds; decimals are read from their string form.
+func numberOf(v any) (float64, bool) {
+	switch x := v.(type) {
+	case int:
+		return float64(x), true
+	case int8:
+		return float64(x), true
+	case int16:
+		return float64(x), true
+	case int32:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case uint:
+		return float64(x), true
+	case uint8:
+		return float64(x), true
+	case uint16:
+		return float64(x), true
+	case uint32:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	case float32:
+		return float64(x), true
+	case float64:
+		return x, true
+	case time.Duration:
+		return float64(x), true
+	case fmt.Stringer:
+		n, err := strconv.ParseFloat(x.String(), 64)
+		return n, err == nil
+	case driver.Valuer:
+		dv, err := x.Value()
+		if err != nil {
+			return 0, false
+		}
+		switch d := dv.(type) {
+		case string:
+			n, err := strconv.ParseFloat(d, 64)
+			return n, err == nil
+		case int64:
+			return float64(d), true
+		case float64:
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// timeOf returns the time of a typed cell value.
+func timeOf(v any) (time.Time, bool) {
+	switch x := v.(type) {
+	case time.Time:
+		return x, true
+	case *time.Time:
+		if x != nil {
+			return *x, true
+		}
+	case driver.Valuer:
+		dv, err := x.Value()
+		if err != nil {
+			return time.Time{}, false
+		}
+		t, ok := dv.(time.Time)
+		return t, ok
+	}
+	return time.Time{}, false
+}
+
+// durationOf returns the duration of a typed cell value; plain numbers are
+// seconds.
+func durationOf(v any) (time.Duration, bool) {
+	if d, ok := v.(time.Duration); ok {
+		return d, true
+	}
+	n, ok := numberOf(v)
+	return time.Duration(n * float64(time.Second)), ok
+}
+
+// formatCell renders a typed value for column col: FormatFunc first, then
+// Format, then the default formatting.
+func formatCell(col ColumnDef, v any) (string, bool) {
+	if col.FormatFunc != nil {
+		return col.FormatFunc(v), true
+	}
+	if col.Format.Kind != FormatNone {
+		return col.Format.format(v)
+	}
+	return formatValue(v)
+}
+
+// columnAlign returns the alignment of a column's cells: Align, or "R" for
+// numeric formats, otherwise "L".
+func columnAlign(col ColumnDef) string {
+	if col.Align != "" {
+		return col.Align
+	}
+	if col.Format.numeric() {
+		return "R"
+	}
+	return "L"
+}
@@ -7,13 +7,15 @@ type GroupedTableComponent struct {
 	BadgeText   string         // right text (supports ":" splitting for two-color)
 	Table       TableComponent // embedded table
 	SpacerAfter float64        // mm of whitespace after the table; default 6
+	NoBookmark  bool           // leave the label out of the PDF outline
 }
 
 // Render draws: SectionLabel → Table → Spacer.
 func (g *GroupedTableComponent) Render(doc *Document) error {
 	label := &SectionLabelComponent{
-		LeftText:  g.Label,
-		RightText: g.BadgeText,
+		LeftText:   g.Label,
+		RightText:  g.BadgeText,
+		NoBookmark: g.NoBookmark,
 	}
 	if err := label.Render(doc); err != nil {
 		return err
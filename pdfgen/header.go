@@ -57,7 +57,7 @@ func (h *HeaderComponent) Render(doc *Document) error {
 		doc.applyFont(titleFont)
 		doc.applyTextColor(doc.theme.PrimaryText)
 		doc.pdf.SetXY(x, startY)
-		doc.pdf.CellFormat(contentW, titleLineH, h.Title, "", 1, "L", false, 0, "")
+		doc.cellFormat(contentW, titleLineH, h.Title, "", 1, "L", false)
 	}
 
 	// Subtitle
@@ -69,7 +69,7 @@ func (h *HeaderComponent) Render(doc *Document) error {
 		doc.applyFont(subtitleFont)
 		doc.applyTextColor(subtitleColor)
 		doc.pdf.SetX(x)
-		doc.pdf.CellFormat(contentW, bodyLineH, h.Subtitle, "", 1, "L", false, 0, "")
+		doc.cellFormat(contentW, bodyLineH, h.Subtitle, "", 1, "L", false)
 	}
 
 	// Additional lines (date range, address, etc.)
@@ -78,7 +78,7 @@ func (h *HeaderComponent) Render(doc *Document) error {
 		doc.applyTextColor(doc.theme.SecondaryText)
 		for _, line := range h.Lines {
 			doc.pdf.SetX(x)
-			doc.pdf.CellFormat(contentW, bodyLineH-0.5, line, "", 1, "L", false, 0, "")
+			doc.cellFormat(contentW, bodyLineH-0.5, line, "", 1, "L", false)
 		}
 	}
 
@@ -0,0 +1,66 @@
+package pdfgen
+
+// HeadingComponent renders a section heading and adds it to the PDF
+// outline, e.g. "IFTA Summary" at level 0 and "Texas" at level 1. Section
+// labels added after a heading are bookmarked one level below it.
+type HeadingComponent struct {
+	Text         string
+	Level        int        // outline level; 0 = top
+	Font         FontConfig // zero value → bold, 14pt at level 0, 12pt at 1, 10pt below
+	Color        Color      // zero value → theme PrimaryText
+	MarginTop    float64    // mm above the heading; default 4, none at the top of a page
+	MarginBottom float64    // mm below the heading; default 2
+	NoBookmark   bool       // leave the heading out of the outline
+}
+
+// headingKeepWithNext is the space, in mm, required below a heading for
+// it to stay on the current page, so a heading is not left alone at the
+// bottom of a page.
+const headingKeepWithNext = 15.0
+
+// Render draws the heading, registers its bookmark and advances the Y cursor.
+func (h *HeadingComponent) Render(doc *Document) error {
+	font := h.Font
+	if font.Family == "" {
+		size := 10.0
+		switch h.Level {
+		case 0:
+			size = 14
+		case 1:
+			size = 12
+		}
+		font = FontConfig{Family: doc.theme.DefaultFont.Family, Size: size, Style: "B"}
+	}
+	color := h.Color
+	if color == (Color{}) {
+		color = doc.theme.PrimaryText
+	}
+	mt := h.MarginTop
+	if mt == 0 {
+		mt = 4
+	}
+	mb := h.MarginBottom
+	if mb == 0 {
+		mb = 2
+	}
+
+	doc.applyFont(font)
+	lineH := font.Size * 0.5
+	lines := doc.splitLines(h.Text, doc.usableWidth())
+	height := float64(len(lines)) * lineH
+
+	if !doc.newPageIfNeeded(mt+height+mb+headingKeepWithNext) && doc.currentY() > doc.pageTop {
+		doc.setY(doc.currentY() + mt)
+	}
+	level := h.Level
+	if !h.NoBookmark {
+		level = doc.bookmark(h.Text, level)
+	}
+	doc.sectionDepth = level + 1
+
+	doc.applyTextColor(color)
+	doc.pdf.SetX(doc.marginL)
+	doc.multiCell(doc.usableWidth(), lineH, h.Text, "L")
+	doc.setY(doc.currentY() + mb)
+	return nil
+}
@@ -0,0 +1,1243 @@
+package pdfgen
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	_ "image/jpeg" // image.DecodeConfig of JPG logos
+	_ "image/png"  // image.DecodeConfig of PNG logos
+	"io/fs"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// mmPerPx converts design pixels to mm. Designs are drawn at 1px = 1pt, so
+// font sizes in px are points.
+const mmPerPx = 0.352778
+
+// HTMLTemplate is a design imported by ImportHTML: the document config,
+// with the theme taken from the design, and the components to add.
+//
+//	tpl, err := pdfgen.ImportHTML(src, os.DirFS("templates"), inter)
+//	doc := pdfgen.New(tpl.Config)
+//	doc.Add(tpl.Components...)
+type HTMLTemplate struct {
+	Config     DocumentConfig
+	Components []Component
+}
+
+// ImportHTML converts a design in a constrained HTML/CSS subset to
+// components, so designers can ship templates without hand-measured
+// pixels. Supported elements are div, h1–h3, p, table/thead/tbody/tr/th/td,
+// img and inline span, b, strong, i, em and br; styles are inline. Of CSS it
+// reads color, background, font-size, font-weight, font-style,
+// font-family, text-align, line-height, padding, margin, border, width,
+// height, display:flex and flex. Lengths are px, pt, mm, cm, in or a
+// percentage of the content width.
+//
+// The body's width picks the page (595px → A4 portrait, 842px → A4
+// landscape, 612px/792px → Letter), its padding the margins, and its
+// color, font-size and font-family the theme. Each element maps onto a
+// component:
+//
+//	h1–h3            HeadingComponent, levels 0–2
+//	p, inline text   wrapped text
+//	table            TableComponent; the first table's header, stripe and
+//	                 border colors go into the theme
+//	img              image in the flow; src is a data: URL or read from
+//	                 assets, which may be nil when every image is inline
+//	flex row + img   LogoComponent beside a HeaderComponent (title, subtitle, lines)
+//	flex row         InfoBlockComponent when every child holds a label and a
+//	                 value; rows of the same shape are merged
+//
+// font-family picks the first family of the list that is one of fonts,
+// compared case-insensitively, or else a core font alias (Arial, sans-serif,
+// Times, serif, Courier, monospace…). fonts go into Config.Fonts.
+//
+// Other elements are errors. Vertical margins collapse as in a browser.
+func ImportHTML(src []byte, assets fs.FS, fonts ...FontFamily) (*HTMLTemplate, error) {
+	root, err := html.Parse(bytes.NewReader(src))
+	if err != nil {
+		return nil, fmt.Errorf("pdfgen: ImportHTML: %w", err)
+	}
+	body := findElement(root, atom.Body)
+	if body == nil {
+		return nil, fmt.Errorf("pdfgen: ImportHTML: no body")
+	}
+	theme := DefaultTheme()
+	css := styleOf(body)
+	st := htmlStyle{fonts: fonts, family: theme.DefaultFont.Family, size: theme.DefaultFont.Size, color: theme.PrimaryText, align: "L"}.with(css)
+	theme.PrimaryText = st.color
+	theme.DefaultFont = FontConfig{Family: st.family, Size: st.size}
+	st.colorSet = false
+
+	cfg := DocumentConfig{PageSize: "A4", Orientation: "portrait", Theme: theme, Fonts: fonts}
+	pageW := 210.0
+	if w, ok := css.length("width", 0); ok {
+		cfg.PageSize, cfg.Orientation, pageW = pageForWidth(w)
+	}
+	pad := css.box("padding", pageW)
+	cfg.MarginTop, cfg.MarginRight, cfg.MarginBottom, cfg.MarginLeft = pad[0], pad[1], pad[2], pad[3]
+	marginL, marginR := cfg.MarginLeft, cfg.MarginRight
+	if marginL == 0 {
+		marginL = 11.3
+	}
+	if marginR == 0 {
+		marginR = 11.3
+	}
+
+	im := &htmlImporter{
+		assets:   assets,
+		tpl:      &HTMLTemplate{Config: cfg},
+		contentW: pageW - marginL - marginR,
+	}
+	if err := im.blocks(body, st); err != nil {
+		return nil, err
+	}
+	return im.tpl, nil
+}
+
+// pageForWidth returns the page whose width is closest to w mm.
+func pageForWidth(w float64) (size, orientation string, pageW float64) {
+	pages := []struct {
+		size, orientation string
+		w                 float64
+	}{
+		{"A4", "portrait", 210},
+		{"A4", "landscape", 297},
+		{"Letter", "portrait", 215.9},
+		{"Letter", "landscape", 279.4},
+	}
+	best := pages[0]
+	for _, p := range pages[1:] {
+		if math.Abs(p.w-w) < math.Abs(best.w-w) {
+			best = p
+		}
+	}
+	return best.size, best.orientation, best.w
+}
+
+// htmlImporter holds the state of one ImportHTML call.
+type htmlImporter struct {
+	assets   fs.FS
+	tpl      *HTMLTemplate
+	contentW float64 // mm; reference of percentages
+	gap      float64 // bottom margin of the last block, collapsed with the next top margin
+	themed   bool    // a table has set the theme's table colors
+	info     *InfoBlockComponent
+	infoW    []float64 // column widths of info
+}
+
+// add appends a component after the collapsed margin above it.
+func (im *htmlImporter) add(c Component, marginTop float64) {
+	if gap := math.Max(im.gap, marginTop); gap > 0 {
+		im.tpl.Components = append(im.tpl.Components, &SpacerComponent{Height: gap})
+	}
+	im.gap = 0
+	im.info = nil
+	im.tpl.Components = append(im.tpl.Components, c)
+}
+
+// blocks imports the children of n. Runs of inline content become text
+// blocks.
+func (im *htmlImporter) blocks(n *html.Node, st htmlStyle) error {
+	var run []*html.Node
+	flush := func() {
+		if len(run) > 0 {
+			im.text(run, st, cssDecls{})
+			run = nil
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if isInline(c) {
+			run = append(run, c)
+			continue
+		}
+		flush()
+		if err := im.block(c, st); err != nil {
+			return err
+		}
+	}
+	flush()
+	return nil
+}
+
+func (im *htmlImporter) block(n *html.Node, st htmlStyle) error {
+	if n.Type != html.ElementNode {
+		return nil
+	}
+	css := styleOf(n)
+	st = st.of(n)
+	switch n.DataAtom {
+	case atom.Div, atom.Section:
+		m := css.box("margin", im.contentW)
+		p := css.box("padding", im.contentW)
+		if n.FirstChild == nil {
+			if h, ok := css.length("height", 0); ok {
+				im.add(&SpacerComponent{Height: h}, m[0])
+				im.gap = m[2]
+			}
+			return nil
+		}
+		if p[0] > 0 {
+			im.add(&SpacerComponent{Height: p[0]}, m[0])
+		} else {
+			im.gap = math.Max(im.gap, m[0])
+		}
+		var err error
+		if css["display"] == "flex" && !strings.HasPrefix(css["flex-direction"], "column") {
+			err = im.flexRow(n, st)
+		} else {
+			err = im.blocks(n, st)
+		}
+		if err != nil {
+			return err
+		}
+		if p[2] > 0 {
+			im.add(&SpacerComponent{Height: p[2]}, 0)
+		}
+		im.gap = math.Max(im.gap, m[2])
+	case atom.H1, atom.H2, atom.H3:
+		im.heading(n, st, css)
+	case atom.P:
+		im.text([]*html.Node{n}, st, css)
+	case atom.Table:
+		return im.table(n, st)
+	case atom.Img:
+		img, err := im.image(n, st, css)
+		if err != nil {
+			return err
+		}
+		m := css.box("margin", im.contentW)
+		im.add(img, m[0])
+		im.gap = m[2]
+	case atom.Br, atom.Style, atom.Script:
+	default:
+		return fmt.Errorf("pdfgen: ImportHTML: unsupported element <%s>", n.Data)
+	}
+	return nil
+}
+
+func (im *htmlImporter) heading(n *html.Node, st htmlStyle, css cssDecls) {
+	text, _, _ := inlineText([]*html.Node{n}, st)
+	h := &HeadingComponent{Text: text}
+	switch n.DataAtom {
+	case atom.H2:
+		h.Level = 1
+	case atom.H3:
+		h.Level = 2
+	}
+	// Without a font in the design the heading keeps its default sizes.
+	if _, ok := css.first("font-size", "font-weight", "font-family"); ok {
+		h.Font = st.font()
+	}
+	if st.colorSet {
+		h.Color = st.color
+	}
+	m := css.box("margin", im.contentW)
+	if _, ok := css.first("margin-top", "margin"); ok {
+		h.MarginTop = math.Max(m[0], im.gap)
+		im.gap = 0
+	}
+	if _, ok := css.first("margin-bottom", "margin"); ok {
+		h.MarginBottom = m[2]
+	}
+	im.add(h, 0)
+}
+
+// text adds the inline content of nodes as a ParagraphComponent, with
+// bold, italic and colored spans as markup. A p has the browser's default
+// 1em margins.
+func (im *htmlImporter) text(nodes []*html.Node, st htmlStyle, css cssDecls) {
+	text := inlineMarkup(nodes, st)
+	if text == "" {
+		return
+	}
+	em := st.size * mmPerPx
+	m := [4]float64{}
+	if len(nodes) == 1 && nodes[0].DataAtom == atom.P {
+		m = [4]float64{em, 0, em, 0}
+		if _, ok := css["margin"]; ok {
+			m = css.box("margin", im.contentW)
+		}
+		if v, ok := css.length("margin-top", im.contentW); ok {
+			m[0] = v
+		}
+		if v, ok := css.length("margin-bottom", im.contentW); ok {
+			m[2] = v
+		}
+	}
+	p := &ParagraphComponent{Text: text, Font: st.font(), Color: st.color, Align: st.align, LineHeight: st.lineHeight() / em}
+	if st.justify {
+		p.Align = "J"
+	}
+	im.add(p, m[0])
+	im.gap = m[2]
+}
+
+// flexRow imports a flex row: an image beside text becomes a logo and a
+// HeaderComponent; children that each hold a label and a value become an
+// InfoBlockComponent. Other rows are imported top to bottom.
+func (im *htmlImporter) flexRow(n *html.Node, st htmlStyle) error {
+	var cells []*html.Node
+	var img *html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type != html.ElementNode {
+			continue
+		}
+		cells = append(cells, c)
+		if i := soleImage(c); i != nil && img == nil {
+			img = i
+		}
+	}
+	if img != nil {
+		return im.headerRow(cells, img, st)
+	}
+	if len(cells) == 0 {
+		return im.blocks(n, st)
+	}
+
+	pieces := make([][]htmlPiece, len(cells))
+	for i, c := range cells {
+		pieces[i] = textPieces(c, st.of(c))
+		if len(pieces[i]) != 2 {
+			return im.blocks(n, st)
+		}
+	}
+	widths := flexWidths(cells, im.contentW)
+	if b := im.info; b != nil && b.Columns == len(cells) && equalWidths(im.infoW, widths) {
+		for _, p := range pieces {
+			b.Items = append(b.Items, InfoItem{Label: p[0].text, Value: p[1].text})
+		}
+		return nil
+	}
+	b := &InfoBlockComponent{Columns: len(cells), ColumnWidths: widths}
+	for i, p := range pieces {
+		b.Items = append(b.Items, InfoItem{Label: p[0].text, Value: p[1].text})
+		if _, ok := styleOf(cells[i]).first("border"); ok {
+			b.ShowBorder = true
+		}
+	}
+	if p := pieces[0][0]; p.sized {
+		b.LabelFont = p.style.font()
+	}
+	if p := pieces[0][1]; p.sized {
+		b.ValueFont = p.style.font()
+	}
+	im.add(b, 0)
+	im.info, im.infoW = b, widths
+	return nil
+}
+
+// headerRow imports a flex row holding an image and the title block.
+func (im *htmlImporter) headerRow(cells []*html.Node, img *html.Node, st htmlStyle) error {
+	var pieces []htmlPiece
+	position := "top-left"
+	for i, c := range cells {
+		if soleImage(c) == img {
+			if i > 0 {
+				position = "top-right"
+			}
+			continue
+		}
+		pieces = append(pieces, textPieces(c, st.of(c))...)
+	}
+	logo, err := im.image(img, st, styleOf(img))
+	if err != nil {
+		return err
+	}
+	logo.Position, logo.Float = position, true
+
+	h := &HeaderComponent{}
+	for i, p := range pieces {
+		switch {
+		case i == 0:
+			h.Title = p.text
+			if p.sized {
+				h.TitleFont = p.style.font()
+			}
+		case i == 1 && (p.heading || p.style.bold) && h.Subtitle == "":
+			h.Subtitle = p.text
+			if p.sized {
+				h.SubtitleFont = p.style.font()
+			}
+			if p.style.colorSet {
+				h.SubtitleColor = p.style.color
+			}
+		default:
+			h.Lines = append(h.Lines, p.text)
+			if p.sized && h.LineFont.Family == "" {
+				h.LineFont = p.style.font()
+			}
+		}
+	}
+	im.add(logo, 0)
+	im.tpl.Components = append(im.tpl.Components, h)
+	return nil
+}
+
+// image returns the flow image of an img element, read from the assets.
+func (im *htmlImporter) image(n *html.Node, st htmlStyle, css cssDecls) (*htmlImage, error) {
+	src := attr(n, "src")
+	var data []byte
+	var err error
+	if rest, ok := strings.CutPrefix(src, "data:"); ok {
+		_, enc, _ := strings.Cut(rest, ",")
+		data, err = base64.StdEncoding.DecodeString(enc)
+	} else if im.assets == nil {
+		return nil, fmt.Errorf("pdfgen: ImportHTML: <img src=%q>: no assets to read it from", shorten(src))
+	} else {
+		data, err = fs.ReadFile(im.assets, strings.TrimPrefix(src, "/"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("pdfgen: ImportHTML: <img src=%q>: %w", shorten(src), err)
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || cfg.Width == 0 {
+		return nil, fmt.Errorf("pdfgen: ImportHTML: <img src=%q>: not a PNG or JPG image", shorten(src))
+	}
+	w, wok := css.length("width", im.contentW)
+	if !wok {
+		w, wok = attrLength(n, "width")
+	}
+	h, hok := css.length("height", 0)
+	if !hok {
+		h, hok = attrLength(n, "height")
+	}
+	switch {
+	case wok && !hok:
+		h = w * float64(cfg.Height) / float64(cfg.Width)
+	case hok && !wok:
+		w = h * float64(cfg.Width) / float64(cfg.Height)
+	case !wok:
+		w, h = float64(cfg.Width)*mmPerPx, float64(cfg.Height)*mmPerPx
+	}
+	img := &htmlImage{LogoComponent: LogoComponent{ImageData: data, Width: w, Height: h}}
+	switch st.align {
+	case "C":
+		img.Position = "top-center"
+	case "R":
+		img.Position = "top-right"
+	}
+	return img, nil
+}
+
+func shorten(s string) string {
+	if len(s) > 40 {
+		return s[:37] + "..."
+	}
+	return s
+}
+
+// table imports a table. Rows in thead, or a first row of th cells, form
+// the header; earlier header rows become HeaderGroups.
+func (im *htmlImporter) table(n *html.Node, st htmlStyle) error {
+	tcss := styleOf(n)
+	st = st.with(tcss)
+	type row struct {
+		n     *html.Node
+		st    htmlStyle
+		cells []*html.Node
+		head  bool
+	}
+	var rows []row
+	var walk func(p *html.Node, st htmlStyle, head bool)
+	walk = func(p *html.Node, st htmlStyle, head bool) {
+		for c := p.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode {
+				continue
+			}
+			switch c.DataAtom {
+			case atom.Thead, atom.Tbody, atom.Tfoot:
+				walk(c, st.of(c), c.DataAtom == atom.Thead)
+			case atom.Tr:
+				r := row{n: c, st: st.of(c), head: head}
+				for td := c.FirstChild; td != nil; td = td.NextSibling {
+					if td.DataAtom == atom.Td || td.DataAtom == atom.Th {
+						r.cells = append(r.cells, td)
+					}
+				}
+				rows = append(rows, r)
+			}
+		}
+	}
+	walk(n, st, false)
+	if len(rows) == 0 {
+		return nil
+	}
+	if !rows[0].head {
+		allTH := len(rows[0].cells) > 0
+		for _, c := range rows[0].cells {
+			allTH = allTH && c.DataAtom == atom.Th
+		}
+		rows[0].head = allTH
+	}
+	nHead := 0
+	for nHead < len(rows) && rows[nHead].head {
+		nHead++
+	}
+
+	t := &TableComponent{ShowHeader: nHead > 0, BorderStyle: "none"}
+	var border cssDecls
+	if _, ok := tcss.first("border"); ok {
+		t.BorderStyle, border = "outer", tcss
+	}
+
+	// Columns come from the last header row, or the widest body row.
+	var colCells []*html.Node
+	if nHead > 0 {
+		colCells = rows[nHead-1].cells
+	} else {
+		for _, r := range rows {
+			if len(r.cells) > len(colCells) {
+				colCells = r.cells
+			}
+		}
+	}
+	var bodyAlign []string
+	if nHead < len(rows) {
+		for _, c := range rows[nHead].cells {
+			bodyAlign = append(bodyAlign, rows[nHead].st.of(c).align)
+		}
+	}
+	for i, c := range colCells {
+		css := styleOf(c)
+		col := ColumnDef{}
+		if w, ok := css.length("width", im.contentW); ok {
+			col.Width = w
+		} else if w, ok := attrLength(c, "width"); ok {
+			col.Width = w
+		} else if f, ok := css.flex(); ok {
+			col.Flex = f
+		}
+		if i < len(bodyAlign) && bodyAlign[i] != "L" {
+			col.Align = bodyAlign[i]
+		}
+		if nHead > 0 {
+			col.Header, _, _ = inlineText([]*html.Node{c}, st)
+			if a := rows[nHead-1].st.of(c).align; a != columnAlign(col) {
+				col.HeaderAlign = a
+			}
+		}
+		t.Columns = append(t.Columns, col)
+		for k := 1; k < colspan(c); k++ {
+			t.Columns = append(t.Columns, ColumnDef{})
+		}
+	}
+	for _, r := range rows[:max(nHead-1, 0)] {
+		var groups []HeaderGroup
+		for _, c := range r.cells {
+			title, _, _ := inlineText([]*html.Node{c}, st)
+			g := HeaderGroup{Title: title, Span: colspan(c)}
+			if a := r.st.of(c).align; a != "C" {
+				g.Align = a
+			}
+			groups = append(groups, g)
+		}
+		t.HeaderGroups = append(t.HeaderGroups, groups)
+	}
+
+	// Fonts, padding and row height follow the first header and body cells.
+	if nHead > 0 && len(rows[nHead-1].cells) > 0 {
+		r := rows[nHead-1]
+		css := styleOf(r.cells[0])
+		hst := r.st.of(r.cells[0])
+		t.HeaderFont = hst.font()
+		t.CellPaddingV, t.CellPaddingH = cellPadding(css, im.contentW)
+		if !im.themed {
+			th := &im.tpl.Config.Theme
+			if c, ok := backgroundOf(css, styleOf(r.n), styleOf(r.n.Parent)); ok {
+				th.TableHeaderBg = c
+			}
+			if hst.colorSet {
+				th.HeaderTextColor = hst.color
+			}
+		}
+	}
+	body := rows[nHead:]
+	if len(body) > 0 && len(body[0].cells) > 0 {
+		css := styleOf(body[0].cells[0])
+		bst := body[0].st.of(body[0].cells[0])
+		if bst.size != im.tpl.Config.Theme.DefaultFont.Size {
+			t.RowFont = FontConfig{Family: bst.family, Size: bst.size}
+		}
+		if v, h := cellPadding(css, im.contentW); v > 0 || h > 0 {
+			t.CellPaddingV, t.CellPaddingH = v, h
+		}
+		for _, c := range body[0].cells {
+			ccss := styleOf(c)
+			switch {
+			case has(ccss, "border"):
+				t.BorderStyle, border = "all", ccss
+			case has(ccss, "border-left", "border-right"):
+				if t.BorderStyle != "all" {
+					t.BorderStyle, border = "columns", ccss
+				}
+			case has(ccss, "border-top", "border-bottom"):
+				if t.BorderStyle == "none" {
+					t.BorderStyle, border = "outer", ccss
+				}
+			}
+		}
+	}
+	for _, r := range rows {
+		if h, ok := styleOf(r.n).length("height", 0); ok {
+			t.MinRowHeight = h
+			break
+		}
+	}
+
+	// Alternating row backgrounds become striping; the first body row is
+	// the "even" one.
+	var bgs []Color
+	var bgSet []bool
+	for _, r := range body {
+		c, ok := backgroundOf(styleOf(r.n))
+		bgs, bgSet = append(bgs, c), append(bgSet, ok)
+	}
+	striped := len(body) > 1 && (bgSet[0] || bgSet[1]) && bgs[0] != bgs[1]
+	if striped && !im.themed {
+		th := &im.tpl.Config.Theme
+		th.TableRowEvenBg, th.TableRowOddBg = Color{255, 255, 255}, Color{255, 255, 255}
+		if bgSet[0] {
+			th.TableRowEvenBg = bgs[0]
+		}
+		if bgSet[1] {
+			th.TableRowOddBg = bgs[1]
+		}
+	}
+	t.RowStriping = striped
+	if border != nil && !im.themed {
+		if c, ok := borderColor(border); ok {
+			im.tpl.Config.Theme.TableBorderColor = c
+		}
+	}
+	im.themed = true
+
+	for i, r := range body {
+		cells := make([]any, 0, len(r.cells))
+		col := 0
+		for _, c := range r.cells {
+			css := styleOf(c)
+			cst := r.st.of(c)
+			text, bold, italic := inlineText([]*html.Node{c}, r.st)
+			cell := Cell{Text: text, Span: colspan(c)}
+			if cst.colorSet && cst.color != im.tpl.Config.Theme.PrimaryText {
+				cell.TextColor = cst.color
+			}
+			if bg, ok := backgroundOf(css); ok {
+				cell.Fill = bg
+			} else if bgSet[i] && !striped {
+				cell.Fill = bgs[i]
+			}
+			cell.Font.Style = htmlStyle{bold: bold, italic: italic}.font().Style
+			if col < len(t.Columns) && cst.align != columnAlign(t.Columns[col]) {
+				cell.Align = cst.align
+			}
+			cells = append(cells, cell)
+			col += cell.Span
+		}
+		t.Data = append(t.Data, cells)
+	}
+	m := tcss.box("margin", im.contentW)
+	im.add(t, m[0])
+	im.gap = m[2]
+	return nil
+}
+
+func colspan(n *html.Node) int {
+	if s, err := strconv.Atoi(attr(n, "colspan")); err == nil && s > 1 {
+		return s
+	}
+	return 1
+}
+
+func cellPadding(css cssDecls, ref float64) (v, h float64) {
+	p := css.box("padding", ref)
+	return p[0], p[3]
+}
+
+// flexWidths returns the widths of the children of a flex row: fixed
+// widths first, then the rest shared by flex weight.
+func flexWidths(cells []*html.Node, contentW float64) []float64 {
+	widths := make([]float64, len(cells))
+	flex := make([]float64, len(cells))
+	remaining, total := contentW, 0.0
+	for i, c := range cells {
+		css := styleOf(c)
+		if w, ok := css.length("width", contentW); ok {
+			widths[i] = w
+			remaining -= w
+			continue
+		}
+		flex[i] = 1
+		if f, ok := css.flex(); ok {
+			flex[i] = f
+		}
+		total += flex[i]
+	}
+	for i := range cells {
+		if flex[i] > 0 {
+			widths[i] = math.Max(remaining, 0) * flex[i] / total
+		}
+	}
+	return widths
+}
+
+func equalWidths(a, b []float64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if math.Abs(a[i]-b[i]) > 0.01 {
+			return false
+		}
+	}
+	return true
+}
+
+// soleImage returns n if it is an img, or the img that is n's only
+// content.
+func soleImage(n *html.Node) *html.Node {
+	if n.DataAtom == atom.Img {
+		return n
+	}
+	var img *html.Node
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.TextNode && strings.TrimSpace(c.Data) == "":
+		case c.Type == html.ElementNode && img == nil && soleImage(c) != nil:
+			img = soleImage(c)
+		case c.Type == html.CommentNode:
+		default:
+			return nil
+		}
+	}
+	return img
+}
+
+// htmlPiece is a block of text inside a flex row child.
+type htmlPiece struct {
+	text    string
+	style   htmlStyle
+	sized   bool // font-size set on the element itself
+	heading bool
+}
+
+// textPieces returns the blocks of text in n: one per block element, one
+// per run of inline content, and one per line break.
+func textPieces(n *html.Node, st htmlStyle) []htmlPiece {
+	var pieces []htmlPiece
+	var run []*html.Node
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		text, bold, italic := inlineText(run, st)
+		for _, line := range strings.Split(text, "\n") {
+			if line != "" {
+				s := st
+				s.bold, s.italic = bold, italic
+				pieces = append(pieces, htmlPiece{text: line, style: s})
+			}
+		}
+		run = nil
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if isInline(c) {
+			run = append(run, c)
+			continue
+		}
+		flush()
+		if c.Type != html.ElementNode {
+			continue
+		}
+		css := styleOf(c)
+		sub := textPieces(c, st.of(c))
+		if len(sub) > 0 {
+			_, sized := css["font-size"]
+			for i := range sub {
+				sub[i].sized = sub[i].sized || sized
+				sub[i].heading = sub[i].heading || c.DataAtom == atom.H1 || c.DataAtom == atom.H2 || c.DataAtom == atom.H3
+			}
+		}
+		pieces = append(pieces, sub...)
+	}
+	flush()
+	return pieces
+}
+
+// htmlImage is an image in the content flow. A floating image, beside a
+// title block, does not advance Y.
+type htmlImage struct {
+	LogoComponent
+	Float bool
+}
+
+// Render draws the image at the current Y.
+func (i *htmlImage) Render(doc *Document) error {
+	if !i.Float {
+		doc.newPageIfNeeded(i.Height)
+	}
+	l := i.LogoComponent
+	l.OffsetY += doc.currentY() - doc.marginT
+	if _, err := l.draw(doc); err != nil {
+		return err
+	}
+	if !i.Float {
+		doc.setY(doc.currentY() + i.Height)
+	}
+	return nil
+}
+
+// htmlStyle is the inherited text style of an element.
+type htmlStyle struct {
+	fonts        []FontFamily // families font-family may name
+	family       string
+	size         float64 // pt
+	bold, italic bool
+	color        Color
+	colorSet     bool // color set by the element or an ancestor below body
+	align        string
+	justify      bool
+	lineH        float64 // mm; 0 → 1.2 × size
+}
+
+// of returns the style of element n inside st: the browser defaults of the
+// element, then its style attribute.
+func (st htmlStyle) of(n *html.Node) htmlStyle {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.Th, atom.B, atom.Strong:
+		st.bold = true
+	case atom.I, atom.Em:
+		st.italic = true
+	}
+	return st.with(styleOf(n))
+}
+
+// with returns st updated by the declarations of an element.
+func (st htmlStyle) with(css cssDecls) htmlStyle {
+	if v, ok := css["color"]; ok {
+		if c, ok := parseCSSColor(v); ok {
+			st.color, st.colorSet = c, true
+		}
+	}
+	if v, ok := css["font-size"]; ok {
+		if pt, ok := parseFontSize(v, st.size); ok {
+			st.size = pt
+		}
+	}
+	switch w := css["font-weight"]; w {
+	case "":
+	case "bold", "bolder":
+		st.bold = true
+	default:
+		n, err := strconv.Atoi(w)
+		st.bold = err == nil && n >= 600
+	}
+	if v, ok := css["font-style"]; ok {
+		st.italic = v == "italic" || v == "oblique"
+	}
+	if v, ok := css["font-family"]; ok {
+		st.family = fontFamily(v, st.family, st.fonts)
+	}
+	switch css["text-align"] {
+	case "left", "start":
+		st.align, st.justify = "L", false
+	case "justify":
+		st.align, st.justify = "L", true
+	case "center":
+		st.align, st.justify = "C", false
+	case "right", "end":
+		st.align, st.justify = "R", false
+	}
+	if v, ok := css["line-height"]; ok {
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			st.lineH = n * st.size * mmPerPx
+		} else if mm, ok := parseLength(v, st.size*mmPerPx); ok {
+			st.lineH = mm
+		}
+	}
+	return st
+}
+
+func (st htmlStyle) font() FontConfig {
+	f := FontConfig{Family: st.family, Size: st.size}
+	if st.bold {
+		f.Style = "B"
+	}
+	if st.italic {
+		f.Style += "I"
+	}
+	return f
+}
+
+func (st htmlStyle) lineHeight() float64 {
+	if st.lineH > 0 {
+		return st.lineH
+	}
+	return 1.2 * st.size * mmPerPx
+}
+
+// fontFamily maps a CSS font-family list to the first of fonts or core
+// fonts it names.
+func fontFamily(list, current string, fonts []FontFamily) string {
+	for _, f := range strings.Split(list, ",") {
+		name := strings.Trim(strings.TrimSpace(f), `"'`)
+		for _, ff := range fonts {
+			if strings.EqualFold(ff.Name, name) {
+				return ff.Name
+			}
+		}
+		switch strings.ToLower(name) {
+		case "arial", "helvetica", "sans-serif", "system-ui":
+			return "Arial"
+		case "times", "times new roman", "serif":
+			return "Times"
+		case "courier", "courier new", "monospace":
+			return "Courier"
+		}
+	}
+	return current
+}
+
+// inlineText returns the text of nodes with whitespace collapsed and br as
+// "\n", and whether all of it is bold or italic.
+func inlineText(nodes []*html.Node, st htmlStyle) (text string, bold, italic bool) {
+	var b strings.Builder
+	bold, italic = true, true
+	seen := false
+	var walk func(n *html.Node, st htmlStyle)
+	walk = func(n *html.Node, st htmlStyle) {
+		switch n.Type {
+		case html.TextNode:
+			b.WriteString(n.Data)
+			if strings.TrimSpace(n.Data) != "" {
+				seen = true
+				bold = bold && st.bold
+				italic = italic && st.italic
+			}
+			return
+		case html.ElementNode:
+		default:
+			return
+		}
+		if n.DataAtom == atom.Br {
+			b.WriteString("\n")
+			return
+		}
+		st = st.of(n)
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, st)
+		}
+	}
+	for _, n := range nodes {
+		if n.Type == html.ElementNode && !isInline(n) {
+			// The block's own style is already in st.
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c, st)
+			}
+			continue
+		}
+		walk(n, st)
+	}
+	lines := strings.Split(b.String(), "\n")
+	for i, l := range lines {
+		lines[i] = strings.Join(strings.Fields(l), " ")
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n"), bold && seen, italic && seen
+}
+
+// inlineMarkup returns the text of nodes as ParagraphComponent markup:
+// spans bolder, more italic or in another color than st are marked up and
+// br is "\n".
+func inlineMarkup(nodes []*html.Node, st htmlStyle) string {
+	var b strings.Builder
+	base := st
+	var walk func(n *html.Node, st htmlStyle)
+	walk = func(n *html.Node, st htmlStyle) {
+		switch n.Type {
+		case html.TextNode:
+			text := strings.Join(strings.Fields(n.Data), " ")
+			if text == "" {
+				b.WriteString(" ")
+				return
+			}
+			if unicode.IsSpace(rune(n.Data[0])) {
+				text = " " + text
+			}
+			if unicode.IsSpace(rune(n.Data[len(n.Data)-1])) {
+				text += " "
+			}
+			var open, end string
+			if st.bold && !base.bold {
+				open, end = open+"**", "**"+end
+			}
+			if st.italic && !base.italic {
+				open, end = open+"*", "*"+end
+			}
+			if st.color != base.color {
+				open += fmt.Sprintf("{#%02X%02X%02X}", st.color.R, st.color.G, st.color.B)
+				end = "{/}" + end
+			}
+			b.WriteString(open + EscapeMarkup(text) + end)
+			return
+		case html.ElementNode:
+		default:
+			return
+		}
+		if n.DataAtom == atom.Br {
+			b.WriteString("\n")
+			return
+		}
+		st = st.of(n)
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c, st)
+		}
+	}
+	for _, n := range nodes {
+		if n.Type == html.ElementNode && !isInline(n) {
+			for c := n.FirstChild; c != nil; c = c.NextSibling {
+				walk(c, st)
+			}
+			continue
+		}
+		walk(n, st)
+	}
+	if strings.TrimSpace(b.String()) == "" {
+		return ""
+	}
+	return b.String()
+}
+
+func isInline(n *html.Node) bool {
+	switch n.Type {
+	case html.TextNode:
+		return true
+	case html.ElementNode:
+		switch n.DataAtom {
+		case atom.Span, atom.B, atom.Strong, atom.I, atom.Em, atom.Br, atom.A:
+			return true
+		}
+	}
+	return false
+}
+
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if f := findElement(c, a); f != nil {
+			return f
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+// attrLength reads a width or height attribute in px.
+func attrLength(n *html.Node, key string) (float64, bool) {
+	v, err := strconv.ParseFloat(strings.TrimSuffix(attr(n, key), "px"), 64)
+	return v * mmPerPx, err == nil && v > 0
+}
+
+// cssDecls are the declarations of a style attribute by property.
+type cssDecls map[string]string
+
+func styleOf(n *html.Node) cssDecls {
+	css := cssDecls{}
+	if n == nil {
+		return css
+	}
+	for _, decl := range strings.Split(attr(n, "style"), ";") {
+		prop, val, ok := strings.Cut(decl, ":")
+		if !ok {
+			continue
+		}
+		val = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(val), "!important"))
+		css[strings.ToLower(strings.TrimSpace(prop))] = val
+	}
+	return css
+}
+
+// first returns the value of the first of props that is declared.
+func (css cssDecls) first(props ...string) (string, bool) {
+	for _, p := range props {
+		if v, ok := css[p]; ok {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// has reports whether any of props draws a border.
+func has(css cssDecls, props ...string) bool {
+	for _, p := range props {
+		if v, ok := css[p]; ok && v != "none" && !strings.HasPrefix(v, "0") {
+			return true
+		}
+	}
+	return false
+}
+
+// length returns a length property in mm; percentages are of ref.
+func (css cssDecls) length(prop string, ref float64) (float64, bool) {
+	v, ok := css[prop]
+	if !ok {
+		return 0, false
+	}
+	return parseLength(v, ref)
+}
+
+// box returns a padding or margin as top, right, bottom, left in mm, from
+// the shorthand and the per-side properties.
+func (css cssDecls) box(prop string, ref float64) [4]float64 {
+	var b [4]float64
+	if v, ok := css[prop]; ok {
+		var vals []float64
+		for _, f := range strings.Fields(v) {
+			n, _ := parseLength(f, ref)
+			vals = append(vals, n)
+		}
+		switch len(vals) {
+		case 1:
+			b = [4]float64{vals[0], vals[0], vals[0], vals[0]}
+		case 2:
+			b = [4]float64{vals[0], vals[1], vals[0], vals[1]}
+		case 3:
+			b = [4]float64{vals[0], vals[1], vals[2], vals[1]}
+		case 4:
+			b = [4]float64{vals[0], vals[1], vals[2], vals[3]}
+		}
+	}
+	for i, side := range []string{"top", "right", "bottom", "left"} {
+		if n, ok := css.length(prop+"-"+side, ref); ok {
+			b[i] = n
+		}
+	}
+	return b
+}
+
+// flex returns the grow factor of flex or flex-grow.
+func (css cssDecls) flex() (float64, bool) {
+	v, ok := css.first("flex-grow", "flex")
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(strings.Fields(v + " ")[0], 64)
+	return f, err == nil && f > 0
+}
+
+// parseLength converts a CSS length to mm. Bare numbers are px;
+// percentages are of ref.
+func parseLength(v string, ref float64) (float64, bool) {
+	v = strings.TrimSpace(v)
+	units := []struct {
+		suffix string
+		mm     float64
+	}{
+		{"px", mmPerPx}, {"pt", mmPerPx}, {"mm", 1}, {"cm", 10}, {"in", 25.4}, {"%", ref / 100},
+	}
+	mm := mmPerPx
+	for _, u := range units {
+		if s, ok := strings.CutSuffix(v, u.suffix); ok {
+			v, mm = s, u.mm
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n * mm, true
+}
+
+// parseFontSize converts a CSS font size to points; em and % are of
+// parent.
+func parseFontSize(v string, parent float64) (float64, bool) {
+	if s, ok := strings.CutSuffix(v, "em"); ok {
+		n, err := strconv.ParseFloat(s, 64)
+		return n * parent, err == nil
+	}
+	mm, ok := parseLength(v, parent*mmPerPx)
+	return mm / mmPerPx, ok
+}
+
+// backgroundOf returns the background color of the first declarations
+// that set one.
+func backgroundOf(decls ...cssDecls) (Color, bool) {
+	for _, css := range decls {
+		if v, ok := css.first("background-color", "background"); ok {
+			for _, f := range strings.Fields(v) {
+				if c, ok := parseCSSColor(f); ok {
+					return c, true
+				}
+			}
+		}
+	}
+	return Color{}, false
+}
+
+// borderColor returns the color of the first border declaration.
+func borderColor(css cssDecls) (Color, bool) {
+	for _, p := range []string{"border-color", "border", "border-left", "border-right", "border-top", "border-bottom"} {
+		for _, f := range strings.Fields(css[p]) {
+			if c, ok := parseCSSColor(f); ok {
+				return c, true
+			}
+		}
+	}
+	return Color{}, false
+}
+
+// parseCSSColor parses #RGB, #RRGGBB, rgb()/rgba() and a few names.
+func parseCSSColor(v string) (Color, bool) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	switch v {
+	case "black":
+		return Color{0, 0, 0}, true
+	case "white":
+		return Color{255, 255, 255}, true
+	case "red":
+		return Color{255, 0, 0}, true
+	case "gray", "grey":
+		return Color{128, 128, 128}, true
+	}
+	if strings.HasPrefix(v, "#") {
+		c, err := parseHexColor(v)
+		return c, err == nil
+	}
+	if args, ok := strings.CutPrefix(v, "rgb"); ok {
+		args = strings.TrimPrefix(args, "a")
+		args = strings.TrimSuffix(strings.TrimPrefix(args, "("), ")")
+		parts := strings.FieldsFunc(args, func(r rune) bool { return r == ',' || r == ' ' || r == '/' })
+		if len(parts) < 3 {
+			return Color{}, false
+		}
+		var rgb [3]int
+		for i := range rgb {
+			n, err := strconv.Atoi(parts[i])
+			if err != nil {
+				return Color{}, false
+			}
+			rgb[i] = n
+		}
+		return Color{rgb[0], rgb[1], rgb[2]}, true
+	}
+	return Color{}, false
+}
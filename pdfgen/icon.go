@@ -0,0 +1,74 @@
+package pdfgen
+
+import "github.com/go-pdf/fpdf"
+
+// Icon is a small vector symbol drawn before a cell's text. Icons are drawn
+// as shapes, so they do not depend on the font's glyph coverage.
+type Icon int
+
+const (
+	// IconNone draws nothing.
+	IconNone Icon = iota
+	// IconDot is a filled circle, e.g. a status light.
+	IconDot
+	// IconWarning is a filled triangle with an exclamation mark.
+	IconWarning
+	// IconArrowUp is an upward triangle.
+	IconArrowUp
+	// IconArrowDown is a downward triangle.
+	IconArrowDown
+	// IconCheck is a check mark.
+	IconCheck
+	// IconCross is an X.
+	IconCross
+)
+
+// iconSize is the edge of the square an icon is drawn in, in mm.
+const iconSize = 3.0
+
+// iconWidth returns the width an icon takes before the cell text, including
+// the gap after it.
+func iconWidth(icon Icon) float64 {
+	if icon == IconNone {
+		return 0
+	}
+	return iconSize + 1
+}
+
+// drawIcon draws icon in the iconSize square at (x, y).
+func drawIcon(doc *Document, icon Icon, x, y float64, color Color) {
+	s := iconSize
+	doc.applyColor(color)
+	switch icon {
+	case IconDot:
+		doc.pdf.Circle(x+s/2, y+s/2, s*0.35, "F")
+	case IconWarning:
+		doc.pdf.Polygon([]fpdf.PointType{{X: x + s/2, Y: y}, {X: x + s, Y: y + s}, {X: x, Y: y + s}}, "F")
+		doc.applyColor(contrastText(doc, color))
+		lw := doc.pdf.GetLineWidth()
+		doc.pdf.SetLineWidth(s * 0.12)
+		doc.pdf.Line(x+s/2, y+s*0.38, x+s/2, y+s*0.7)
+		doc.pdf.SetLineWidth(lw)
+		doc.pdf.Circle(x+s/2, y+s*0.85, s*0.06, "F")
+	case IconArrowUp:
+		doc.pdf.Polygon([]fpdf.PointType{{X: x + s/2, Y: y + s*0.1}, {X: x + s*0.95, Y: y + s*0.85}, {X: x + s*0.05, Y: y + s*0.85}}, "F")
+	case IconArrowDown:
+		doc.pdf.Polygon([]fpdf.PointType{{X: x + s*0.05, Y: y + s*0.15}, {X: x + s*0.95, Y: y + s*0.15}, {X: x + s/2, Y: y + s*0.9}}, "F")
+	case IconCheck, IconCross:
+		lw := doc.pdf.GetLineWidth()
+		doc.pdf.SetLineWidth(s * 0.14)
+		doc.pdf.SetLineCapStyle("round")
+		if icon == IconCheck {
+			doc.pdf.MoveTo(x+s*0.1, y+s*0.55)
+			doc.pdf.LineTo(x+s*0.4, y+s*0.85)
+			doc.pdf.LineTo(x+s*0.9, y+s*0.15)
+			doc.pdf.DrawPath("D")
+		} else {
+			d := s * 0.2
+			doc.pdf.Line(x+d, y+d, x+s-d, y+s-d)
+			doc.pdf.Line(x+s-d, y+d, x+d, y+s-d)
+		}
+		doc.pdf.SetLineCapStyle("butt")
+		doc.pdf.SetLineWidth(lw)
+	}
+}
@@ -110,13 +110,13 @@ func (b *InfoBlockComponent) Render(doc *Document) error {
 		doc.applyFont(labelFont)
 		doc.applyTextColor(doc.theme.SecondaryText)
 		doc.pdf.SetXY(x+paddingH, y+paddingV)
-		doc.pdf.CellFormat(w-2*paddingH, labelLineH, item.Label, "", 0, "L", false, 0, "")
+		doc.cellFormat(w-2*paddingH, labelLineH, item.Label, "", 0, "L", false)
 
 		// Value — primary color, bold
 		doc.applyFont(valueFont)
 		doc.applyTextColor(doc.theme.PrimaryText)
 		doc.pdf.SetXY(x+paddingH, y+paddingV+labelLineH)
-		doc.pdf.CellFormat(w-2*paddingH, valueLineH, item.Value, "", 0, "L", false, 0, "")
+		doc.cellFormat(w-2*paddingH, valueLineH, item.Value, "", 0, "L", false)
 	}
 
 	doc.setY(startY + totalH)
@@ -0,0 +1,462 @@
+package pdfgen
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// InvoiceLine is one item of an invoice. Amounts are exact decimals: ints,
+// decimal strings such as "1204.50", floats (taken at their shortest decimal
+// form) or decimal types such as pgtype.Numeric.
+type InvoiceLine struct {
+	SKU             string
+	Description     string
+	Quantity        any // nil → 1
+	UnitPrice       any // nil → 0
+	DiscountPercent any // percent off quantity × unit price, e.g. "10"; nil → none
+	Discount        any // amount off the line, after DiscountPercent; nil → none
+}
+
+// invoiceAmounts is the computed money of one InvoiceLine. Every amount is
+// rounded to the currency, so Gross − Discount = Total on the printed page.
+type invoiceAmounts struct {
+	qty, price      *big.Rat
+	gross           *big.Rat // quantity × unit price
+	percent         *big.Rat // nil without DiscountPercent
+	discount, total *big.Rat
+}
+
+// amounts computes the line in a currency of decimals digits.
+func (l InvoiceLine) amounts(decimals int) (invoiceAmounts, error) {
+	var a invoiceAmounts
+	dec := func(name string, v, def any) (*big.Rat, error) {
+		if v == nil {
+			v = def
+		}
+		r, ok := decimalOf(v)
+		if !ok {
+			return nil, fmt.Errorf("%s %v is not a decimal", name, v)
+		}
+		return r, nil
+	}
+	var err error
+	if a.qty, err = dec("quantity", l.Quantity, 1); err != nil {
+		return a, err
+	}
+	if a.price, err = dec("unit price", l.UnitPrice, 0); err != nil {
+		return a, err
+	}
+	a.gross = roundDecimal(new(big.Rat).Mul(a.qty, a.price), decimals)
+	a.discount = new(big.Rat)
+	if l.DiscountPercent != nil {
+		if a.percent, err = dec("discount percent", l.DiscountPercent, nil); err != nil {
+			return a, err
+		}
+		off := new(big.Rat).Mul(a.gross, a.percent)
+		a.discount.Add(a.discount, roundDecimal(off.Quo(off, big.NewRat(100, 1)), decimals))
+	}
+	if l.Discount != nil {
+		off, err := dec("discount", l.Discount, nil)
+		if err != nil {
+			return a, err
+		}
+		a.discount.Add(a.discount, roundDecimal(off, decimals))
+	}
+	a.total = new(big.Rat).Sub(a.gross, a.discount)
+	return a, nil
+}
+
+// invoiceCurrency resolves the currency format of the invoice components.
+func invoiceCurrency(f ValueFormat) ValueFormat {
+	if f.Kind == FormatNone {
+		return CurrencyFormat("$", 2)
+	}
+	return f
+}
+
+// invoiceSubtotal sums the totals of lines.
+func invoiceSubtotal(lines []InvoiceLine, decimals int) (*big.Rat, error) {
+	sum := new(big.Rat)
+	for i, l := range lines {
+		a, err := l.amounts(decimals)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		sum.Add(sum, a.total)
+	}
+	return sum, nil
+}
+
+// InvoiceLinesComponent renders the line items of an invoice as a table:
+// SKU, description, quantity, unit price, discount and line total. Money is
+// computed in exact decimals and rounded to the currency per line, so the
+// printed columns add up. The SKU and Discount columns are left out when no
+// line uses them.
+type InvoiceLinesComponent struct {
+	Lines       []InvoiceLine
+	Currency    ValueFormat // symbol and decimals; zero value → CurrencyFormat("$", 2)
+	RowStriping bool        // alternate row background colors
+	BorderStyle string      // as TableComponent; default "columns"
+	HeaderFont  FontConfig  // zero value → theme default, bold
+	RowFont     FontConfig  // zero value → theme default
+}
+
+// Render draws the table and advances the Y cursor. A line whose amounts are
+// not decimals makes it return an error.
+func (c *InvoiceLinesComponent) Render(doc *Document) error {
+	if len(c.Lines) == 0 {
+		return nil
+	}
+	cur := invoiceCurrency(c.Currency)
+	amounts := make([]invoiceAmounts, len(c.Lines))
+	hasSKU, hasDiscount := false, false
+	for i, l := range c.Lines {
+		a, err := l.amounts(cur.Decimals)
+		if err != nil {
+			return fmt.Errorf("pdfgen: InvoiceLinesComponent: line %d: %w", i+1, err)
+		}
+		amounts[i] = a
+		hasSKU = hasSKU || l.SKU != ""
+		hasDiscount = hasDiscount || a.discount.Sign() != 0
+	}
+
+	var cols []ColumnDef
+	if hasSKU {
+		cols = append(cols, ColumnDef{Header: "SKU", Width: 26, Align: "L", Overflow: OverflowTruncate})
+	}
+	cols = append(cols,
+		ColumnDef{Header: "Description", Align: "L"},
+		ColumnDef{Header: "Qty", Width: 20, Align: "R"},
+		ColumnDef{Header: "Unit Price", Width: 26, Align: "R"},
+	)
+	if hasDiscount {
+		cols = append(cols, ColumnDef{Header: "Discount", Width: 32, Align: "R"})
+	}
+	cols = append(cols, ColumnDef{Header: "Amount", Width: 28, Align: "R"})
+
+	rows := make([][]string, len(c.Lines))
+	for i, l := range c.Lines {
+		a := amounts[i]
+		// Unit prices keep the digits they were given, e.g. $0.035 per mile.
+		price := cur
+		price.Decimals = max(cur.Decimals, decimalPlaces(a.price, 4))
+		var row []string
+		if hasSKU {
+			row = append(row, l.SKU)
+		}
+		row = append(row, l.Description, formatDecimal(a.qty, decimalPlaces(a.qty, 4)), formatMoney(a.price, price))
+		if hasDiscount {
+			discount := ""
+			if a.discount.Sign() != 0 {
+				discount = formatMoney(a.discount, cur)
+				if a.percent != nil && l.Discount == nil {
+					discount += " (" + formatDecimal(a.percent, decimalPlaces(a.percent, 2)) + "%)"
+				}
+			}
+			row = append(row, discount)
+		}
+		rows[i] = append(row, formatMoney(a.total, cur))
+	}
+
+	border := c.BorderStyle
+	if border == "" {
+		border = "columns"
+	}
+	table := &TableComponent{
+		Columns:     cols,
+		Rows:        rows,
+		ShowHeader:  true,
+		RowStriping: c.RowStriping,
+		BorderStyle: border,
+		HeaderFont:  c.HeaderFont,
+		RowFont:     c.RowFont,
+	}
+	return table.Render(doc)
+}
+
+// InvoiceTax is one tax line of an InvoiceTotalsComponent, e.g. a state's
+// sales tax.
+type InvoiceTax struct {
+	Jurisdiction string // e.g. "TX"
+	Label        string // "" → "TX tax (6.25%)" from Jurisdiction and Rate
+	Rate         any    // percent, e.g. "6.25"; used when Amount is nil
+	Taxable      any    // amount Rate applies to; nil → the subtotal
+	Amount       any    // nil → Rate × Taxable, rounded to the currency
+}
+
+// InvoiceCredit is an amount subtracted from the amount due, e.g. a
+// prepayment or a service credit.
+type InvoiceCredit struct {
+	Label  string // default "Credit"
+	Amount any    // positive
+}
+
+// InvoiceTotalsComponent renders the totals box of an invoice, aligned to
+// the right margin: subtotal, one line per tax, credits, and the amount due.
+// Amounts are exact decimals, as in InvoiceLine. The box is kept together on
+// one page.
+type InvoiceTotalsComponent struct {
+	Lines         []InvoiceLine // the subtotal is the sum of their totals
+	Subtotal      any           // overrides the sum of Lines
+	Taxes         []InvoiceTax
+	Credits       []InvoiceCredit
+	Currency      ValueFormat // zero value → CurrencyFormat("$", 2)
+	SubtotalLabel string      // default "Subtotal"
+	DueLabel      string      // default "Amount Due"
+	Width         float64     // mm; default 80
+	Font          FontConfig  // zero value → theme default
+	MarginBottom  float64     // mm below the box; default 6
+}
+
+// totalsRow is one label and amount of the totals box.
+type totalsRow struct {
+	label  string
+	amount string
+}
+
+// Render draws the box and advances the Y cursor. An amount that is not a
+// decimal makes it return an error.
+func (t *InvoiceTotalsComponent) Render(doc *Document) error {
+	cur := invoiceCurrency(t.Currency)
+	rows, due, err := t.rows(cur)
+	if err != nil {
+		return fmt.Errorf("pdfgen: InvoiceTotalsComponent: %w", err)
+	}
+
+	font := t.Font
+	if font.Family == "" {
+		font.Family = doc.theme.DefaultFont.Family
+	}
+	if font.Size == 0 {
+		font.Size = doc.theme.DefaultFont.Size
+	}
+	w := t.Width
+	if w == 0 {
+		w = 80
+	}
+	w = min(w, doc.usableWidth())
+	mb := t.MarginBottom
+	if mb == 0 {
+		mb = 6
+	}
+	const padV = 1.5
+	rowH := font.Size*0.5 + 2*padV
+	dueH := rowH + 1
+	doc.newPageIfNeeded(float64(len(rows))*rowH + dueH + mb)
+
+	x, top := doc.marginL+doc.usableWidth()-w, doc.currentY()
+	y := top
+	doc.applyFont(font)
+	for _, r := range rows {
+		doc.pdf.SetXY(x, y)
+		doc.applyTextColor(doc.theme.SecondaryText)
+		doc.cellFormat(w, rowH, r.label, "", 0, "L", false)
+		doc.pdf.SetXY(x, y)
+		doc.applyTextColor(doc.theme.PrimaryText)
+		doc.cellFormat(w, rowH, r.amount, "", 0, "R", false)
+		y += rowH
+	}
+
+	doc.applyColor(doc.theme.TableRowEvenBg)
+	doc.pdf.Rect(x, y, w, dueH, "F")
+	doc.applyColor(doc.theme.TableBorderColor)
+	doc.pdf.Line(x, y, x+w, y)
+	doc.applyFont(FontConfig{Family: font.Family, Size: font.Size, Style: "B"})
+	doc.applyTextColor(doc.theme.PrimaryText)
+	label := t.DueLabel
+	if label == "" {
+		label = "Amount Due"
+	}
+	doc.pdf.SetXY(x, y)
+	doc.cellFormat(w, dueH, label, "", 0, "L", false)
+	doc.pdf.SetXY(x, y)
+	doc.cellFormat(w, dueH, formatMoney(due, cur), "", 0, "R", false)
+	y += dueH
+
+	doc.applyColor(doc.theme.TableBorderColor)
+	doc.pdf.Rect(x, top, w, y-top, "D")
+	doc.setY(y + mb)
+	return nil
+}
+
+// rows computes the rows above the amount due, and the amount due.
+func (t *InvoiceTotalsComponent) rows(cur ValueFormat) ([]totalsRow, *big.Rat, error) {
+	var subtotal *big.Rat
+	if t.Subtotal != nil {
+		r, ok := decimalOf(t.Subtotal)
+		if !ok {
+			return nil, nil, fmt.Errorf("subtotal %v is not a decimal", t.Subtotal)
+		}
+		subtotal = roundDecimal(r, cur.Decimals)
+	} else {
+		var err error
+		if subtotal, err = invoiceSubtotal(t.Lines, cur.Decimals); err != nil {
+			return nil, nil, err
+		}
+	}
+	label := t.SubtotalLabel
+	if label == "" {
+		label = "Subtotal"
+	}
+	rows := []totalsRow{{label, formatMoney(subtotal, cur)}}
+	due := new(big.Rat).Set(subtotal)
+
+	for i, tax := range t.Taxes {
+		var rate *big.Rat
+		if tax.Rate != nil {
+			r, ok := decimalOf(tax.Rate)
+			if !ok {
+				return nil, nil, fmt.Errorf("tax %d: rate %v is not a decimal", i+1, tax.Rate)
+			}
+			rate = r
+		}
+		var amount *big.Rat
+		switch {
+		case tax.Amount != nil:
+			r, ok := decimalOf(tax.Amount)
+			if !ok {
+				return nil, nil, fmt.Errorf("tax %d: amount %v is not a decimal", i+1, tax.Amount)
+			}
+			amount = roundDecimal(r, cur.Decimals)
+		case rate != nil:
+			base := subtotal
+			if tax.Taxable != nil {
+				r, ok := decimalOf(tax.Taxable)
+				if !ok {
+					return nil, nil, fmt.Errorf("tax %d: taxable %v is not a decimal", i+1, tax.Taxable)
+				}
+				base = r
+			}
+			amount = new(big.Rat).Mul(base, rate)
+			amount = roundDecimal(amount.Quo(amount, big.NewRat(100, 1)), cur.Decimals)
+		default:
+			return nil, nil, fmt.Errorf("tax %d: needs Rate or Amount", i+1)
+		}
+		label := tax.Label
+		if label == "" {
+			label = "Tax"
+			if tax.Jurisdiction != "" {
+				label = tax.Jurisdiction + " tax"
+			}
+			if rate != nil {
+				label += " (" + formatDecimal(rate, decimalPlaces(rate, 4)) + "%)"
+			}
+		}
+		rows = append(rows, totalsRow{label, formatMoney(amount, cur)})
+		due.Add(due, amount)
+	}
+
+	for i, cr := range t.Credits {
+		r, ok := decimalOf(cr.Amount)
+		if !ok {
+			return nil, nil, fmt.Errorf("credit %d: amount %v is not a decimal", i+1, cr.Amount)
+		}
+		amount := roundDecimal(r, cur.Decimals)
+		label := cr.Label
+		if label == "" {
+			label = "Credit"
+		}
+		rows = append(rows, totalsRow{label, formatMoney(new(big.Rat).Neg(amount), cur)})
+		due.Sub(due, amount)
+	}
+	return rows, due, nil
+}
+
+// Address is a postal address with a caption, e.g. the bill-to party of an
+// invoice.
+type Address struct {
+	Label string   // caption, e.g. "Bill To"
+	Name  string   // first line, bold, e.g. the carrier's legal name
+	Lines []string // street, city/state/ZIP, USDOT number, contact
+}
+
+// AddressBlockComponent renders addresses side by side in equal columns,
+// e.g. bill-to and ship-to on an invoice. Long lines wrap within their
+// column; the block is kept together on one page.
+type AddressBlockComponent struct {
+	Addresses    []Address
+	Width        float64    // mm; 0 = full usable width
+	Gap          float64    // mm between columns; default 6
+	Font         FontConfig // zero value → theme default; captions 2pt smaller
+	MarginBottom float64    // mm below the block; default 6
+}
+
+// Render draws the addresses and advances the Y cursor past the tallest.
+func (b *AddressBlockComponent) Render(doc *Document) error {
+	if len(b.Addresses) == 0 {
+		return nil
+	}
+	font := b.Font
+	if font.Family == "" {
+		font.Family = doc.theme.DefaultFont.Family
+	}
+	if font.Size == 0 {
+		font.Size = doc.theme.DefaultFont.Size
+	}
+	width := b.Width
+	if width == 0 {
+		width = doc.usableWidth()
+	}
+	gap := b.Gap
+	if gap == 0 {
+		gap = 6
+	}
+	mb := b.MarginBottom
+	if mb == 0 {
+		mb = 6
+	}
+	n := float64(len(b.Addresses))
+	colW := (width - gap*(n-1)) / n
+	caption := FontConfig{Family: font.Family, Size: font.Size - 2}
+	bold := FontConfig{Family: font.Family, Size: font.Size, Style: "B"}
+	captionH, lineH := caption.Size*0.5, font.Size*0.5
+
+	// Wrap every address first to find the height of the block.
+	names := make([][]string, len(b.Addresses))
+	lines := make([][]string, len(b.Addresses))
+	height := 0.0
+	for i, a := range b.Addresses {
+		h := 0.0
+		if a.Label != "" {
+			h += captionH + 1
+		}
+		doc.applyFont(bold)
+		if a.Name != "" {
+			names[i] = doc.splitLines(a.Name, colW)
+		}
+		doc.applyFont(font)
+		for _, l := range a.Lines {
+			lines[i] = append(lines[i], doc.splitLines(l, colW)...)
+		}
+		h += float64(len(names[i])+len(lines[i])) * lineH
+		height = max(height, h)
+	}
+	doc.newPageIfNeeded(height + mb)
+
+	top := doc.currentY()
+	for i, a := range b.Addresses {
+		x, y := doc.marginL+float64(i)*(colW+gap), top
+		if a.Label != "" {
+			doc.applyFont(caption)
+			doc.applyTextColor(doc.theme.SecondaryText)
+			doc.pdf.SetXY(x, y)
+			doc.cellFormat(colW, captionH, a.Label, "", 0, "L", false)
+			y += captionH + 1
+		}
+		doc.applyTextColor(doc.theme.PrimaryText)
+		doc.applyFont(bold)
+		for _, l := range names[i] {
+			doc.pdf.SetXY(x, y)
+			doc.cellFormat(colW, lineH, l, "", 0, "L", false)
+			y += lineH
+		}
+		doc.applyFont(font)
+		for _, l := range lines[i] {
+			doc.pdf.SetXY(x, y)
+			doc.cellFormat(colW, lineH, l, "", 0, "L", false)
+			y += lineH
+		}
+	}
+	doc.setY(top + height + mb)
+	return nil
+}
@@ -0,0 +1,428 @@
+package pdfgen
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+)
+
+// TimePoint is one sample of a TimeSeries. A NaN Value marks missing data and
+// breaks the line.
+type TimePoint struct {
+	At    time.Time
+	Value float64
+}
+
+// TimeSeries is one named line of a LineChartComponent.
+type TimeSeries struct {
+	Name   string
+	Points []TimePoint // need not be sorted
+	Color  Color       // zero value → next color of the chart palette
+}
+
+// ChartThreshold is a horizontal reference line, e.g. a 70 mph speed limit.
+type ChartThreshold struct {
+	Value float64
+	Label string // printed at the right end of the line, e.g. "70 mph"
+	Color Color  // zero value → red
+}
+
+// LineChartComponent plots one or more numeric series against a time axis:
+// speed, odometer, engine hours, fuel level.
+//
+// Tick labels are rendered in Location, so a movement report for an EST
+// carrier reads in EST regardless of how the timestamps were stored. The line
+// is broken at NaN values and wherever consecutive points are further apart
+// than MaxGap. The chart is kept together on one page.
+type LineChartComponent struct {
+	Series      []TimeSeries
+	Thresholds  []ChartThreshold     // optional horizontal reference lines
+	Location    *time.Location       // tick label time zone; nil → location of the first point
+	TimeLayout  string               // tick label layout; "" → chosen from the tick interval
+	MaxGap      time.Duration        // break the line when points are further apart; 0 = never
+	ShowMinMax  bool                 // mark and label the min and max point of every series
+	ShowLegend  bool                 // draw a legend above the plot
+	IncludeZero bool                 // extend the value axis down (or up) to zero
+	YLabel      string               // unit caption above the value axis, e.g. "mph"
+	Width       float64              // mm; 0 = full usable width
+	Height      float64              // mm plot height; default 60
+	Ticks       int                  // approximate number of intervals on each axis; default 6
+	LineWidth   float64              // mm; default 0.4
+	ValueFormat func(float64) string // zero value → thousands separator, decimals as needed by the ticks
+	Font        FontConfig           // zero value → theme default at 7pt
+}
+
+// Render draws the chart and advances the Y cursor.
+func (c *LineChartComponent) Render(doc *Document) error {
+	series := make([][]TimePoint, len(c.Series))
+	var tMin, tMax time.Time
+	vMin, vMax := math.Inf(1), math.Inf(-1)
+	for i, s := range c.Series {
+		pts := append([]TimePoint(nil), s.Points...)
+		sort.SliceStable(pts, func(a, b int) bool { return pts[a].At.Before(pts[b].At) })
+		series[i] = pts
+		for _, p := range pts {
+			if tMin.IsZero() || p.At.Before(tMin) {
+				tMin = p.At
+			}
+			if tMax.IsZero() || p.At.After(tMax) {
+				tMax = p.At
+			}
+			if !math.IsNaN(p.Value) && !math.IsInf(p.Value, 0) {
+				vMin, vMax = math.Min(vMin, p.Value), math.Max(vMax, p.Value)
+			}
+		}
+	}
+	if tMin.IsZero() {
+		return nil
+	}
+	if !tMax.After(tMin) {
+		return fmt.Errorf("pdfgen: LineChartComponent needs points spanning more than one instant")
+	}
+	if math.IsInf(vMin, 1) {
+		vMin, vMax = 0, 0
+	}
+	for _, th := range c.Thresholds {
+		vMin, vMax = math.Min(vMin, th.Value), math.Max(vMax, th.Value)
+	}
+	if c.IncludeZero {
+		vMin, vMax = math.Min(vMin, 0), math.Max(vMax, 0)
+	}
+
+	font := c.Font
+	if font.Family == "" {
+		font = doc.theme.DefaultFont
+		font.Size = 7
+	}
+	width := c.Width
+	if width == 0 {
+		width = doc.usableWidth()
+	}
+	plotH := c.Height
+	if plotH == 0 {
+		plotH = 60
+	}
+	ticks := c.Ticks
+	if ticks == 0 {
+		ticks = 6
+	}
+	lineW := c.LineWidth
+	if lineW == 0 {
+		lineW = 0.4
+	}
+	loc := c.Location
+	if loc == nil {
+		loc = tMin.Location()
+	}
+
+	axisMin, axisMax, step := c.valueScale(vMin, vMax, ticks)
+	format := c.ValueFormat
+	if format == nil {
+		decimals := tickDecimals(step)
+		format = func(v float64) string { return formatNumber(v, decimals) }
+	}
+
+	const (
+		labelH   = 3.5
+		timeH    = 5.0
+		captionH = 4.0
+	)
+
+	var items []legendItem
+	legendH := 0.0
+	if c.ShowLegend {
+		for i, s := range c.Series {
+			items = append(items, legendItem{Label: s.Name, Color: seriesColor(doc, s.Color, i)})
+		}
+		_, _, legendH = legendLayout(doc, items, width, font)
+		legendH += 2
+	}
+	topPad := labelH / 2
+	if c.YLabel != "" {
+		topPad = captionH
+	}
+	doc.newPageIfNeeded(legendH + topPad + plotH + timeH)
+
+	startY := doc.currentY()
+	if len(items) > 0 {
+		drawLegend(doc, items, doc.marginL, startY, width, font)
+	}
+
+	doc.applyFont(font)
+	cm := doc.pdf.GetCellMargin()
+	axisLabelW := 0.0
+	for v := axisMin; v <= axisMax+step/2; v += step {
+		axisLabelW = math.Max(axisLabelW, doc.stringWidth(format(v)))
+	}
+	axisLabelW += 2 * cm
+
+	plotX := doc.marginL + axisLabelW
+	plotY := startY + legendH + topPad
+	plotW := width - axisLabelW
+	span := tMax.Sub(tMin).Seconds()
+	xAt := func(t time.Time) float64 {
+		return plotX + t.Sub(tMin).Seconds()/span*plotW
+	}
+	yAt := func(v float64) float64 {
+		return plotY + plotH - (v-axisMin)/(axisMax-axisMin)*plotH
+	}
+
+	// ── Value axis ───────────────────────────────────────────────────────────
+	if c.YLabel != "" {
+		doc.applyTextColor(doc.theme.SecondaryText)
+		doc.pdf.SetXY(doc.marginL, plotY-captionH)
+		doc.cellFormat(width, captionH, c.YLabel, "", 0, "L", false)
+	}
+	for v := axisMin; v <= axisMax+step/2; v += step {
+		y := yAt(v)
+		doc.applyColor(doc.theme.TableBorderColor)
+		doc.pdf.Line(plotX, y, plotX+plotW, y)
+		doc.applyTextColor(doc.theme.SecondaryText)
+		doc.pdf.SetXY(doc.marginL, y-labelH/2)
+		doc.cellFormat(axisLabelW, labelH, format(v), "", 0, "R", false)
+	}
+
+	// ── Time axis ────────────────────────────────────────────────────────────
+	interval := timeTickInterval(tMax.Sub(tMin), ticks)
+	layout := c.TimeLayout
+	if layout == "" {
+		layout = timeTickLayout(interval, tMin.In(loc), tMax.In(loc))
+	}
+	doc.applyColor(doc.theme.SecondaryText)
+	doc.pdf.Line(plotX, plotY+plotH, plotX+plotW, plotY+plotH)
+	for _, t := range timeTicks(tMin, tMax, interval, loc) {
+		x := xAt(t)
+		doc.applyColor(doc.theme.TableBorderColor)
+		doc.pdf.Line(x, plotY, x, plotY+plotH)
+		doc.applyColor(doc.theme.SecondaryText)
+		doc.pdf.Line(x, plotY+plotH, x, plotY+plotH+1)
+		doc.applyTextColor(doc.theme.SecondaryText)
+		doc.pdf.SetXY(x-12, plotY+plotH+1)
+		doc.cellFormat(24, timeH-1, t.In(loc).Format(layout), "", 0, "C", false)
+	}
+
+	// ── Thresholds ───────────────────────────────────────────────────────────
+	for _, th := range c.Thresholds {
+		color := th.Color
+		if color == (Color{}) {
+			color = Color{R: 239, G: 68, B: 68} // #EF4444 red-500
+		}
+		y := yAt(th.Value)
+		doc.applyColor(color)
+		doc.pdf.SetDashPattern([]float64{1.5, 1}, 0)
+		doc.pdf.Line(plotX, y, plotX+plotW, y)
+		doc.pdf.SetDashPattern(nil, 0)
+		if th.Label != "" {
+			doc.applyTextColor(color)
+			labelW := doc.stringWidth(th.Label) + 2*cm
+			doc.pdf.SetXY(plotX+plotW-labelW, y-labelH)
+			doc.cellFormat(labelW, labelH, th.Label, "", 0, "R", false)
+		}
+	}
+
+	// ── Series ───────────────────────────────────────────────────────────────
+	savedLineW := doc.pdf.GetLineWidth()
+	doc.pdf.SetLineWidth(lineW)
+	for i, pts := range series {
+		color := seriesColor(doc, c.Series[i].Color, i)
+		doc.applyColor(color)
+		for _, run := range splitRuns(pts, c.MaxGap) {
+			run = decimatePoints(run, xAt, 0.25)
+			if len(run) == 1 {
+				doc.pdf.Circle(xAt(run[0].At), yAt(run[0].Value), lineW, "F")
+				continue
+			}
+			doc.pdf.MoveTo(xAt(run[0].At), yAt(run[0].Value))
+			for _, p := range run[1:] {
+				doc.pdf.LineTo(xAt(p.At), yAt(p.Value))
+			}
+			doc.pdf.DrawPath("D")
+		}
+	}
+	doc.pdf.SetLineWidth(savedLineW)
+
+	// ── Min / max annotations ────────────────────────────────────────────────
+	if c.ShowMinMax {
+		for i, pts := range series {
+			lo, hi, ok := minMaxPoints(pts)
+			if !ok {
+				continue
+			}
+			color := seriesColor(doc, c.Series[i].Color, i)
+			c.annotate(doc, hi, xAt, yAt, -labelH, labelH, color, format, loc, plotX, plotW)
+			if lo != hi {
+				c.annotate(doc, lo, xAt, yAt, 0.8, labelH, color, format, loc, plotX, plotW)
+			}
+		}
+	}
+
+	doc.setY(plotY + plotH + timeH + 2)
+	return nil
+}
+
+// valueScale returns the nice value-axis range for [lo, hi].
+func (c *LineChartComponent) valueScale(lo, hi float64, ticks int) (min, max, step float64) {
+	if lo == hi {
+		pad := math.Max(math.Abs(lo)*0.1, 1)
+		lo, hi = lo-pad, hi+pad
+	}
+	return niceScale(lo, hi, ticks)
+}
+
+// annotate draws a marker at p and its value label offset dy mm vertically.
+func (c *LineChartComponent) annotate(doc *Document, p TimePoint, xAt func(time.Time) float64, yAt func(float64) float64, dy, h float64, color Color, format func(float64) string, loc *time.Location, plotX, plotW float64) {
+	x, y := xAt(p.At), yAt(p.Value)
+	doc.applyColor(color)
+	doc.pdf.Circle(x, y, 0.7, "F")
+
+	text := format(p.Value) + " @ " + p.At.In(loc).Format("15:04")
+	w := doc.stringWidth(text) + 2*doc.pdf.GetCellMargin()
+	lx := math.Min(math.Max(x-w/2, plotX), plotX+plotW-w)
+	doc.applyTextColor(color)
+	doc.pdf.SetXY(lx, y+dy)
+	doc.cellFormat(w, h, text, "", 0, "C", false)
+}
+
+// splitRuns splits sorted points into continuous runs, breaking at NaN values
+// and at gaps longer than maxGap (when maxGap > 0).
+func splitRuns(pts []TimePoint, maxGap time.Duration) [][]TimePoint {
+	var runs [][]TimePoint
+	var run []TimePoint
+	for _, p := range pts {
+		if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			if len(run) > 0 {
+				runs = append(runs, run)
+				run = nil
+			}
+			continue
+		}
+		if len(run) > 0 && maxGap > 0 && p.At.Sub(run[len(run)-1].At) > maxGap {
+			runs = append(runs, run)
+			run = nil
+		}
+		run = append(run, p)
+	}
+	if len(run) > 0 {
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// decimatePoints reduces a run to at most four points (first, min, max, last)
+// per bucket of width mm along the X axis, preserving the visible shape of
+// dense series such as 1-second GPS pings.
+func decimatePoints(run []TimePoint, xAt func(time.Time) float64, width float64) []TimePoint {
+	if len(run) < 4 {
+		return run
+	}
+	out := make([]TimePoint, 0, len(run))
+	flush := func(bucket []TimePoint) {
+		first, last := 0, len(bucket)-1
+		lo, hi := first, first
+		for i, p := range bucket {
+			if p.Value < bucket[lo].Value {
+				lo = i
+			}
+			if p.Value > bucket[hi].Value {
+				hi = i
+			}
+		}
+		idx := []int{first, lo, hi, last}
+		sort.Ints(idx)
+		prev := -1
+		for _, i := range idx {
+			if i != prev {
+				out = append(out, bucket[i])
+				prev = i
+			}
+		}
+	}
+	start := 0
+	bucket := math.Floor(xAt(run[0].At) / width)
+	for i := 1; i < len(run); i++ {
+		if b := math.Floor(xAt(run[i].At) / width); b != bucket {
+			flush(run[start:i])
+			start, bucket = i, b
+		}
+	}
+	flush(run[start:])
+	return out
+}
+
+// minMaxPoints returns the lowest and highest valid point of pts.
+func minMaxPoints(pts []TimePoint) (lo, hi TimePoint, ok bool) {
+	for _, p := range pts {
+		if math.IsNaN(p.Value) || math.IsInf(p.Value, 0) {
+			continue
+		}
+		if !ok {
+			lo, hi, ok = p, p, true
+			continue
+		}
+		if p.Value < lo.Value {
+			lo = p
+		}
+		if p.Value > hi.Value {
+			hi = p
+		}
+	}
+	return lo, hi, ok
+}
+
+// timeTickIntervals are the candidate spacings for time-axis ticks.
+var timeTickIntervals = []time.Duration{
+	time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour,
+	24 * time.Hour, 2 * 24 * time.Hour, 7 * 24 * time.Hour, 14 * 24 * time.Hour,
+}
+
+// timeTickInterval picks the smallest candidate interval that yields at most
+// ticks intervals over span.
+func timeTickInterval(span time.Duration, ticks int) time.Duration {
+	for _, iv := range timeTickIntervals {
+		if span/iv <= time.Duration(ticks) {
+			return iv
+		}
+	}
+	return timeTickIntervals[len(timeTickIntervals)-1]
+}
+
+// timeTickLayout chooses a label layout for the interval and span.
+func timeTickLayout(interval time.Duration, from, to time.Time) string {
+	switch {
+	case interval >= 24*time.Hour:
+		return "Jan 2"
+	case from.YearDay() != to.YearDay() || from.Year() != to.Year():
+		return "Jan 2 15:04"
+	default:
+		return "15:04"
+	}
+}
+
+// timeTicks returns tick instants in [from, to] aligned to interval boundaries
+// of wall-clock time in loc, so hourly ticks land on :00 local time.
+func timeTicks(from, to time.Time, interval time.Duration, loc *time.Location) []time.Time {
+	local := from.In(loc)
+	var t time.Time
+	if interval >= 24*time.Hour {
+		t = time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+	} else {
+		midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		elapsed := local.Sub(midnight)
+		t = midnight.Add(elapsed / interval * interval)
+	}
+	var ticks []time.Time
+	days := int(interval / (24 * time.Hour))
+	for !t.After(to) {
+		if !t.Before(from) {
+			ticks = append(ticks, t)
+		}
+		if days > 0 {
+			t = t.AddDate(0, 0, days)
+		} else {
+			t = t.Add(interval)
+		}
+	}
+	return ticks
+}
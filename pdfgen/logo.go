@@ -2,6 +2,7 @@ package pdfgen
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"fmt"
 
 	"github.com/go-pdf/fpdf"
@@ -25,11 +26,17 @@ type LogoComponent struct {
 // Render places the image at the configured position.
 // The Y cursor is NOT advanced — logos float above the layout flow.
 func (l *LogoComponent) Render(doc *Document) error {
+	_, err := l.draw(doc)
+	return err
+}
+
+// draw places the image and returns its height in mm.
+func (l *LogoComponent) draw(doc *Document) (float64, error) {
 	if l.ImagePath == "" && len(l.ImageData) == 0 {
-		return fmt.Errorf("pdfgen: LogoComponent requires ImagePath or ImageData")
+		return 0, fmt.Errorf("pdfgen: LogoComponent requires ImagePath or ImageData")
 	}
 	if l.Width <= 0 {
-		return fmt.Errorf("pdfgen: LogoComponent Width must be > 0")
+		return 0, fmt.Errorf("pdfgen: LogoComponent Width must be > 0")
 	}
 
 	pdf := doc.pdf
@@ -49,23 +56,39 @@ func (l *LogoComponent) Render(doc *Document) error {
 	// Save current Y so we can restore it after ImageOptions (which may move cursor).
 	savedY := doc.currentY()
 
-	imgName := l.ImagePath
-	opts := fpdf.ImageOptions{}
-
-	if len(l.ImageData) > 0 {
-		doc.imageCount++
-		imgName = fmt.Sprintf("pdfgen_img_%d", doc.imageCount)
-		imgType := detectImageType(l.ImageData)
-		opts.ImageType = imgType
-		reader := bytes.NewReader(l.ImageData)
-		pdf.RegisterImageOptionsReader(imgName, opts, reader)
-	}
+	imgName, opts := doc.image(l.ImagePath, l.ImageData)
 
 	pdf.ImageOptions(imgName, x, y, l.Width, l.Height, false, opts, 0, "")
 
 	// Restore Y — logos do not participate in the content flow.
 	doc.setY(savedY)
-	return nil
+
+	h := l.Height
+	if info := pdf.GetImageInfo(imgName); h == 0 && info != nil && info.Width() > 0 {
+		h = l.Width * info.Height() / info.Width()
+	}
+	return h, nil
+}
+
+// image returns the fpdf name and options of an image given by path or
+// data. Data is registered on first use and looked up by its content, so an
+// image repeated on every page, e.g. by the page header, is embedded once,
+// while a buffer reused for another image registers that image afresh.
+func (d *Document) image(path string, data []byte) (string, fpdf.ImageOptions) {
+	opts := fpdf.ImageOptions{}
+	if len(data) == 0 {
+		return path, opts
+	}
+	opts.ImageType = detectImageType(data)
+	key := sha256.Sum256(data)
+	name, ok := d.images[key]
+	if !ok {
+		d.imageCount++
+		name = fmt.Sprintf("pdfgen_img_%d", d.imageCount)
+		d.pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(data))
+		d.images[key] = name
+	}
+	return name, opts
 }
 
 // detectImageType returns "JPG" or "PNG" based on the file magic bytes.
@@ -0,0 +1,113 @@
+package pdfgen
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// decimalOf returns the exact value of an amount: an integer, a decimal
+// string such as "1204.50", a float taken at its shortest decimal form (so
+// 0.1 is exactly one tenth), or a decimal type read from its string form
+// (fmt.Stringer or driver.Valuer, e.g. pgtype.Numeric or json.Number).
+func decimalOf(v any) (*big.Rat, bool) {
+	switch x := v.(type) {
+	case int:
+		return new(big.Rat).SetInt64(int64(x)), true
+	case int8:
+		return new(big.Rat).SetInt64(int64(x)), true
+	case int16:
+		return new(big.Rat).SetInt64(int64(x)), true
+	case int32:
+		return new(big.Rat).SetInt64(int64(x)), true
+	case int64:
+		return new(big.Rat).SetInt64(x), true
+	case uint:
+		return new(big.Rat).SetUint64(uint64(x)), true
+	case uint8:
+		return new(big.Rat).SetUint64(uint64(x)), true
+	case uint16:
+		return new(big.Rat).SetUint64(uint64(x)), true
+	case uint32:
+		return new(big.Rat).SetUint64(uint64(x)), true
+	case uint64:
+		return new(big.Rat).SetUint64(x), true
+	case float32:
+		if math.IsNaN(float64(x)) || math.IsInf(float64(x), 0) {
+			return nil, false
+		}
+		return decimalOf(strconv.FormatFloat(float64(x), 'f', -1, 32))
+	case float64:
+		if math.IsNaN(x) || math.IsInf(x, 0) {
+			return nil, false
+		}
+		return decimalOf(strconv.FormatFloat(x, 'f', -1, 64))
+	case string:
+		r, ok := new(big.Rat).SetString(strings.ReplaceAll(strings.TrimSpace(x), ",", ""))
+		return r, ok
+	case fmt.Stringer:
+		return decimalOf(x.String())
+	case driver.Valuer:
+		dv, err := x.Value()
+		if err != nil || dv == nil {
+			return nil, false
+		}
+		return decimalOf(dv)
+	}
+	return nil, false
+}
+
+// roundDecimal rounds r to decimals digits, halves away from zero as on
+// invoices: 2.345 → 2.35, -2.345 → -2.35.
+func roundDecimal(r *big.Rat, decimals int) *big.Rat {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals)), nil)
+	num := new(big.Int).Mul(r.Num(), scale)
+	q, m := new(big.Int).QuoRem(num, r.Denom(), new(big.Int))
+	// QuoRem truncates toward zero; round up the magnitude when the
+	// remainder is at least half the denominator.
+	if m.Abs(m).Lsh(m, 1).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(num.Sign())))
+	}
+	return new(big.Rat).SetFrac(q, scale)
+}
+
+// decimalPlaces returns the digits after the decimal point needed to print r
+// exactly, at most limit.
+func decimalPlaces(r *big.Rat, limit int) int {
+	d := 0
+	for d < limit && roundDecimal(r, d).Cmp(r) != 0 {
+		d++
+	}
+	return d
+}
+
+// formatDecimal prints r rounded to decimals digits with thousands
+// separators, e.g. "-1,204.50".
+func formatDecimal(r *big.Rat, decimals int) string {
+	s := roundDecimal(r, decimals).FloatString(decimals)
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i:]
+	}
+	if strings.Trim(s, "0.") == "" {
+		sign = ""
+	}
+	return sign + groupThousands(intPart) + frac
+}
+
+// formatMoney prints r as money in the symbol and decimals of f, like
+// CurrencyFormat: "$1,204.50", "-$12.50".
+func formatMoney(r *big.Rat, f ValueFormat) string {
+	s := formatDecimal(r, f.Decimals)
+	if rest, ok := strings.CutPrefix(s, "-"); ok {
+		return "-" + f.Symbol + rest
+	}
+	return f.Symbol + s
+}
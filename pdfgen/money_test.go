@@ -0,0 +1,266 @@
+package pdfgen
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func rat(t *testing.T, s string) *big.Rat {
+	t.Helper()
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		t.Fatalf("bad decimal %q", s)
+	}
+	return r
+}
+
+func TestDecimalOf(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want string // "" → not a decimal
+	}{
+		{"int", 42, "42"},
+		{"int8", int8(-7), "-7"},
+		{"int16", int16(1200), "1200"},
+		{"uint8", uint8(255), "255"},
+		{"uint16", uint16(65535), "65535"},
+		{"uint64", uint64(math.MaxUint64), "18446744073709551615"},
+		{"float32 shortest form", float32(0.1), "0.1"},
+		{"float32 cents", float32(19.99), "19.99"},
+		{"float64 shortest form", 0.1, "0.1"},
+		{"string with separators", " 1,204.50 ", "1204.5"},
+		{"json.Number", json.Number("29.99"), "29.99"},
+		{"float32 NaN", float32(math.NaN()), ""},
+		{"float64 infinity", math.Inf(1), ""},
+		{"text", "n/a", ""},
+		{"bool", true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := decimalOf(tt.in)
+			if tt.want == "" {
+				if ok {
+					t.Fatalf("decimalOf(%v) = %v, want not a decimal", tt.in, got)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("decimalOf(%v) not a decimal, want %s", tt.in, tt.want)
+			}
+			if got.Cmp(rat(t, tt.want)) != 0 {
+				t.Errorf("decimalOf(%v) = %s, want %s", tt.in, got.RatString(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundDecimal(t *testing.T) {
+	tests := []struct {
+		in       string
+		decimals int
+		want     string
+	}{
+		{"2.345", 2, "2.35"},
+		{"-2.345", 2, "-2.35"},
+		{"2.3449", 2, "2.34"},
+		{"-2.3449", 2, "-2.34"},
+		{"0.005", 2, "0.01"},
+		{"-0.005", 2, "-0.01"},
+		{"2.5", 0, "3"},
+		{"-2.5", 0, "-3"},
+		{"1/3", 2, "0.33"},
+		{"2/3", 2, "0.67"},
+		{"1.0005", 3, "1.001"},
+	}
+	for _, tt := range tests {
+		got := roundDecimal(rat(t, tt.in), tt.decimals)
+		if got.Cmp(rat(t, tt.want)) != 0 {
+			t.Errorf("roundDecimal(%s, %d) = %s, want %s", tt.in, tt.decimals, got.FloatString(tt.decimals), tt.want)
+		}
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	usd := CurrencyFormat("$", 2)
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"1204.5", "$1,204.50"},
+		{"-12.5", "-$12.50"},
+		{"1234567.891", "$1,234,567.89"},
+		{"-0.004", "$0.00"},
+		{"0", "$0.00"},
+	}
+	for _, tt := range tests {
+		if got := formatMoney(rat(t, tt.in), usd); got != tt.want {
+			t.Errorf("formatMoney(%s) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestInvoiceLineAmounts(t *testing.T) {
+	tests := []struct {
+		name                   string
+		line                   InvoiceLine
+		gross, discount, total string
+	}{
+		{
+			name:  "quantity defaults to one",
+			line:  InvoiceLine{UnitPrice: "100.00"},
+			gross: "100", discount: "0", total: "100",
+		},
+		{
+			name:  "float price at its decimal value",
+			line:  InvoiceLine{Quantity: 3, UnitPrice: 0.1},
+			gross: "0.3", discount: "0", total: "0.3",
+		},
+		{
+			name:  "gross rounded half away from zero",
+			line:  InvoiceLine{Quantity: "1234.5", UnitPrice: "0.035"},
+			gross: "43.21", discount: "0", total: "43.21",
+		},
+		{
+			name:  "percent discount rounded per line",
+			line:  InvoiceLine{UnitPrice: "19.99", DiscountPercent: "12.5"},
+			gross: "19.99", discount: "2.5", total: "17.49",
+		},
+		{
+			name:  "percent of a rounded gross",
+			line:  InvoiceLine{Quantity: 3, UnitPrice: "0.115", DiscountPercent: 10},
+			gross: "0.35", discount: "0.04", total: "0.31",
+		},
+		{
+			name:  "percent and amount discounts add up",
+			line:  InvoiceLine{Quantity: 2, UnitPrice: "10", DiscountPercent: "5", Discount: "1.005"},
+			gross: "20", discount: "2.01", total: "17.99",
+		},
+		{
+			name:  "negative line",
+			line:  InvoiceLine{Quantity: -1, UnitPrice: "2.345"},
+			gross: "-2.35", discount: "0", total: "-2.35",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, err := tt.line.amounts(2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range []struct {
+				field     string
+				got, want *big.Rat
+			}{
+				{"gross", a.gross, rat(t, tt.gross)},
+				{"discount", a.discount, rat(t, tt.discount)},
+				{"total", a.total, rat(t, tt.total)},
+			} {
+				if c.got.Cmp(c.want) != 0 {
+					t.Errorf("%s = %s, want %s", c.field, c.got.FloatString(2), c.want.FloatString(2))
+				}
+			}
+		})
+	}
+}
+
+func TestInvoiceLineAmountsError(t *testing.T) {
+	if _, err := (InvoiceLine{UnitPrice: "abc"}).amounts(2); err == nil {
+		t.Error("amounts with a non-decimal unit price: want error")
+	}
+}
+
+func TestInvoiceTotalsRows(t *testing.T) {
+	lines := []InvoiceLine{
+		{Quantity: 2, UnitPrice: "49.99"},
+		{UnitPrice: "100.00", DiscountPercent: 10},
+	} // subtotal 189.98
+	tests := []struct {
+		name   string
+		totals InvoiceTotalsComponent
+		rows   []totalsRow
+		due    string
+	}{
+		{
+			name:   "subtotal only",
+			totals: InvoiceTotalsComponent{Lines: lines},
+			rows:   []totalsRow{{"Subtotal", "$189.98"}},
+			due:    "189.98",
+		},
+		{
+			name: "tax on the subtotal",
+			totals: InvoiceTotalsComponent{Lines: lines, Taxes: []InvoiceTax{
+				{Jurisdiction: "TN", Rate: "9.25"},
+			}},
+			rows: []totalsRow{{"Subtotal", "$189.98"}, {"TN tax (9.25%)", "$17.57"}},
+			due:  "207.55",
+		},
+		{
+			name: "tax on a taxable part",
+			totals: InvoiceTotalsComponent{Lines: lines, Taxes: []InvoiceTax{
+				{Jurisdiction: "TX", Rate: 6.25, Taxable: "90.00"},
+			}},
+			rows: []totalsRow{{"Subtotal", "$189.98"}, {"TX tax (6.25%)", "$5.63"}},
+			due:  "195.61",
+		},
+		{
+			name: "fixed tax amount",
+			totals: InvoiceTotalsComponent{Subtotal: "10", Taxes: []InvoiceTax{
+				{Label: "Filing fee", Amount: "1.005"},
+			}},
+			rows: []totalsRow{{"Subtotal", "$10.00"}, {"Filing fee", "$1.01"}},
+			due:  "11.01",
+		},
+		{
+			name: "credits exceed the total",
+			totals: InvoiceTotalsComponent{Lines: lines,
+				Taxes:   []InvoiceTax{{Rate: "5"}},
+				Credits: []InvoiceCredit{{Label: "Prepaid", Amount: "150"}, {Amount: 100}},
+			},
+			rows: []totalsRow{
+				{"Subtotal", "$189.98"},
+				{"Tax (5%)", "$9.50"},
+				{"Prepaid", "-$150.00"},
+				{"Credit", "-$100.00"},
+			},
+			due: "-50.52",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, due, err := tt.totals.rows(CurrencyFormat("$", 2))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(rows) != len(tt.rows) {
+				t.Fatalf("rows = %v, want %v", rows, tt.rows)
+			}
+			for i := range rows {
+				if rows[i] != tt.rows[i] {
+					t.Errorf("row %d = %v, want %v", i, rows[i], tt.rows[i])
+				}
+			}
+			if due.Cmp(rat(t, tt.due)) != 0 {
+				t.Errorf("due = %s, want %s", due.FloatString(2), tt.due)
+			}
+		})
+	}
+}
+
+func TestInvoiceTotalsRowsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		totals InvoiceTotalsComponent
+	}{
+		{"bad subtotal", InvoiceTotalsComponent{Subtotal: "x"}},
+		{"tax without rate or amount", InvoiceTotalsComponent{Taxes: []InvoiceTax{{Jurisdiction: "TN"}}}},
+		{"bad credit", InvoiceTotalsComponent{Credits: []InvoiceCredit{{Amount: "ten"}}}},
+	}
+	for _, tt := range tests {
+		if _, _, err := tt.totals.rows(CurrencyFormat("$", 2)); err == nil {
+			t.Errorf("%s: want error", tt.name)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package pdfgen
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// outlineEntry is one PDF outline bookmark, kept for the table of contents.
+type outlineEntry struct {
+	title string
+	level int
+	page  int
+	y     float64
+}
+
+// Bookmark adds a PDF outline entry pointing at the current position.
+// level 0 is the top of the outline; a level more than one below the
+// previous entry is raised so the outline stays well formed.
+// HeadingComponent, SectionLabelComponent and GroupedTableComponent add
+// their bookmarks automatically; call Bookmark from custom components.
+func (d *Document) Bookmark(title string, level int) *Document {
+	d.ensurePage()
+	d.bookmark(title, level)
+	return d
+}
+
+// bookmark adds an outline entry and returns its level after correction.
+// Empty titles are skipped.
+func (d *Document) bookmark(title string, level int) int {
+	deepest := 0
+	if n := len(d.outline); n > 0 {
+		deepest = d.outline[n-1].level + 1
+	}
+	level = min(max(level, 0), deepest)
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return level
+	}
+	d.outline = append(d.outline, outlineEntry{title: title, level: level, page: d.pdf.PageNo(), y: d.currentY()})
+	return level
+}
+
+// putBookmarks hands the outline to fpdf. fpdf places every bookmark by the
+// height of the last page, so entries on pages of another height are
+// shifted to match.
+func (d *Document) putBookmarks() {
+	d.endPage()
+	lastH := d.page(d.pdf.PageCount()).sec.h
+	for _, e := range d.outline {
+		d.pdf.SetPage(e.page)
+		d.pdf.Bookmark(d.outlineTitle(e.title), e.level, e.y+lastH-d.page(e.page).sec.h)
+	}
+}
+
+// outlineTitle encodes a bookmark title for fpdf. fpdf converts titles to
+// UTF-16 only while a UTF-8 font is active, so for core fonts non-ASCII
+// titles are encoded here.
+func (d *Document) outlineTitle(s string) string {
+	if d.face != nil {
+		return s
+	}
+	ascii := true
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return s
+	}
+	var b strings.Builder
+	b.WriteString("\xFE\xFF")
+	for _, u := range utf16.Encode([]rune(s)) {
+		b.WriteByte(byte(u >> 8))
+		b.WriteByte(byte(u))
+	}
+	return b.String()
+}
@@ -0,0 +1,139 @@
+package pdfgen
+
+import "fmt"
+
+// PageHeaderComponent renders a header at the top of every page, so pages
+// after the first keep the branding and report title.
+// Register it via doc.SetPageHeader() — do NOT pass to doc.Add().
+//
+// The header is drawn from the top margin: the logo, the text line, then
+// Components. Content on each page starts Gap mm below the lowest of them,
+// including rows a table carries over to a new page.
+//
+// The text fields support the placeholders of FooterComponent.
+type PageHeaderComponent struct {
+	LeftText      string               // left-aligned; supports placeholders
+	CenterText    string               // center-aligned; supports placeholders
+	RightText     string               // right-aligned; supports placeholders
+	Logo          *LogoComponent       // drawn at its Position on every page; embedded once
+	Components    []Component          // rendered below the text line, e.g. a HeaderComponent
+	ShowBorder    bool                 // draw a thin rule under the header
+	Font          FontConfig           // zero value → theme default at 8pt
+	TextColor     Color                // zero value → theme SecondaryText
+	Gap           float64              // mm between the header and the content; default 3
+	FirstPage     *PageHeaderComponent // header of page 1; nil → this header
+	EvenPages     *PageHeaderComponent // header of even pages; nil → this header
+	SkipFirstPage bool                 // no header on page 1, e.g. when it starts with a HeaderComponent
+}
+
+// Render implements Component so PageHeaderComponent can also be used
+// standalone, but it is normally called automatically on every new page.
+// Standalone, {total} is replaced at output time.
+func (h *PageHeaderComponent) Render(doc *Document) error {
+	top := doc.currentY()
+	if err := h.render(doc); err != nil {
+		return err
+	}
+	y := doc.currentY()
+	doc.setY(top)
+	h.drawText(doc, doc.pdf.PageNo(), 0, doc.vars)
+	doc.setY(y)
+	return nil
+}
+
+// renderPage lays out the header variant of the current page. Its text is
+// drawn by drawText once the page count is known. Errors stop the document
+// like component errors.
+func (h *PageHeaderComponent) renderPage(doc *Document) {
+	n := doc.pdf.PageNo()
+	hdr := h
+	switch {
+	case n == 1 && h.SkipFirstPage:
+		return
+	case n == 1 && h.FirstPage != nil:
+		hdr = h.FirstPage
+	case n%2 == 0 && h.EvenPages != nil:
+		hdr = h.EvenPages
+	}
+	doc.page(n).header = hdr
+	if err := hdr.render(doc); err != nil && doc.err == nil {
+		doc.err = fmt.Errorf("pdfgen: PageHeaderComponent: %w", err)
+	}
+}
+
+// hasText reports whether the header has a text line.
+func (h *PageHeaderComponent) hasText() bool {
+	return h.LeftText != "" || h.CenterText != "" || h.RightText != ""
+}
+
+// render draws the header, except its text line, from the current Y and
+// leaves Y where the page content starts.
+func (h *PageHeaderComponent) render(doc *Document) error {
+	pdf := doc.pdf
+	top := doc.currentY()
+	bottom := top
+
+	if h.Logo != nil {
+		lh, err := h.Logo.draw(doc)
+		if err != nil {
+			return err
+		}
+		bottom = max(bottom, doc.marginT+h.Logo.OffsetY+lh)
+	}
+
+	if h.hasText() {
+		bottom = max(bottom, top+pageHeaderLineH)
+	}
+
+	if len(h.Components) > 0 {
+		doc.setY(bottom)
+		for _, c := range h.Components {
+			if err := c.Render(doc); err != nil {
+				return fmt.Errorf("%T render: %w", c, err)
+			}
+		}
+		bottom = max(bottom, doc.currentY())
+	}
+
+	if bottom == top {
+		return nil
+	}
+	if h.ShowBorder {
+		bottom++
+		doc.applyColor(doc.theme.TableBorderColor)
+		pdf.Line(doc.marginL, bottom, doc.marginL+doc.usableWidth(), bottom)
+	}
+	gap := h.Gap
+	if gap == 0 {
+		gap = 3
+	}
+	doc.setY(bottom + gap)
+	return nil
+}
+
+// pageHeaderLineH is the height of the header text line in mm.
+const pageHeaderLineH = 5.0
+
+// drawText draws the text line of page n from the current Y.
+func (h *PageHeaderComponent) drawText(doc *Document, page, total int, vars map[string]string) {
+	if !h.hasText() {
+		return
+	}
+	font := h.Font
+	if font.Family == "" {
+		font = doc.theme.DefaultFont
+		font.Size = 8
+	}
+	doc.applyFont(font)
+	color := h.TextColor
+	if color == (Color{}) {
+		color = doc.theme.SecondaryText
+	}
+	doc.applyTextColor(color)
+
+	w := doc.usableWidth()
+	doc.pdf.SetX(doc.marginL)
+	doc.cellFormat(w/3, pageHeaderLineH, doc.expandPlaceholders(h.LeftText, page, total, vars), "", 0, "L", false)
+	doc.cellFormat(w/3, pageHeaderLineH, doc.expandPlaceholders(h.CenterText, page, total, vars), "", 0, "C", false)
+	doc.cellFormat(w/3, pageHeaderLineH, doc.expandPlaceholders(h.RightText, page, total, vars), "", 0, "R", false)
+}
@@ -0,0 +1,398 @@
+package pdfgen
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParagraphComponent renders free text wrapped to the usable width, e.g.
+// certification statements, disclaimers and inspection remarks.
+//
+// Text takes a small inline markup:
+//
+//	**bold**   *italic*   {accent}colored{/}   \* a literal asterisk
+//
+// Colors are {primary}, {secondary}, {accent} (theme colors) or {#RRGGBB};
+// {/} ends the innermost color. Other {…} is drawn as-is. Use EscapeMarkup
+// for data inserted into markup, or Raw to turn markup off. "\n" breaks the
+// line.
+//
+// A paragraph that does not fit breaks across pages between lines, leaving
+// at least Orphans lines at the bottom of the page and Widows lines at the
+// top of the next; when it cannot, the whole paragraph moves to the next
+// page.
+type ParagraphComponent struct {
+	Text         string
+	Raw          bool       // draw Text as-is, without markup
+	Font         FontConfig // zero value → theme DefaultFont
+	Color        Color      // zero value → theme PrimaryText
+	Align        string     // "L", "C", "R" or "J" (justify); default "L"
+	LineHeight   float64    // line spacing as a multiple of the font size; default 1.4
+	MarginBottom float64    // mm below the last line
+	Orphans      int        // minimum lines left at the bottom of a page; default 2
+	Widows       int        // minimum lines carried to the top of the next page; default 2
+}
+
+// EscapeMarkup escapes s so ParagraphComponent draws it literally, e.g. a
+// driver's remark inserted into "**Remarks:** " + EscapeMarkup(remark).
+func EscapeMarkup(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `*`, `\*`, `{`, `\{`)
+	return r.Replace(s)
+}
+
+// textStyle is the markup style of a run of text.
+type textStyle struct {
+	bold, italic bool
+	color        Color
+}
+
+// textSeg is a run of one style within a word.
+type textSeg struct {
+	text  string
+	style textStyle
+	w     float64
+}
+
+// textWord is a word of a paragraph, drawn after a space of width space
+// unless it starts the line.
+type textWord struct {
+	segs  []textSeg
+	w     float64
+	space float64
+	br    bool // a line break follows the word
+}
+
+// textLine is a laid-out line.
+type textLine struct {
+	words []textWord
+	w     float64 // natural width, single spaces included
+	last  bool    // ends the text or a "\n"; not justified
+}
+
+// Render draws the paragraph and advances the Y cursor.
+func (p *ParagraphComponent) Render(doc *Document) error {
+	lines, font, lineH := p.layout(doc)
+	orphans := p.Orphans
+	if orphans <= 0 {
+		orphans = 2
+	}
+	widows := p.Widows
+	if widows <= 0 {
+		widows = 2
+	}
+
+	for len(lines) > 0 {
+		n := int(math.Floor((doc.pageBottom() - doc.currentY() + 1e-9) / lineH))
+		atTop := doc.currentY() <= doc.pageTop+1e-9
+		if doc.inHeader {
+			n = len(lines)
+		} else if n < len(lines) {
+			// Leave Widows lines for the next page and Orphans on this one.
+			if rest := len(lines) - n; rest < widows {
+				n -= widows - rest
+			}
+			if n < orphans {
+				n = 0
+			}
+			if n == 0 && atTop {
+				n = max(int(math.Floor((doc.pageBottom()-doc.currentY())/lineH)), 1)
+			}
+		}
+		n = min(n, len(lines))
+		for _, l := range lines[:n] {
+			p.drawLine(doc, l, font, lineH)
+		}
+		lines = lines[n:]
+		if len(lines) > 0 {
+			doc.addPage()
+		}
+	}
+	doc.setY(doc.currentY() + p.MarginBottom)
+	return nil
+}
+
+// layout returns the lines of the paragraph, its font and the line height
+// in mm.
+func (p *ParagraphComponent) layout(doc *Document) (lines []textLine, font FontConfig, lineH float64) {
+	font = p.Font
+	if font.Family == "" {
+		font.Family = doc.theme.DefaultFont.Family
+	}
+	if font.Size == 0 {
+		font.Size = doc.theme.DefaultFont.Size
+	}
+	color := p.Color
+	if color == (Color{}) {
+		color = doc.theme.PrimaryText
+	}
+	lh := p.LineHeight
+	if lh == 0 {
+		lh = 1.4
+	}
+	lineH = lh * font.Size * mmPerPx
+
+	var words []textWord
+	if p.Raw {
+		words = splitWords([]textSeg{{text: p.Text, style: textStyle{color: color}}})
+	} else {
+		words = splitWords(parseMarkup(p.Text, color, doc.theme))
+	}
+	return layoutLines(doc, words, font, doc.usableWidth()), font, lineH
+}
+
+// height returns the height of the paragraph drawn on one page, margin
+// included.
+func (p *ParagraphComponent) height(doc *Document) float64 {
+	lines, _, lineH := p.layout(doc)
+	return float64(len(lines))*lineH + p.MarginBottom
+}
+
+// drawLine draws l at the current Y and moves below it.
+func (p *ParagraphComponent) drawLine(doc *Document, l textLine, font FontConfig, lineH float64) {
+	maxW := doc.usableWidth()
+	x := doc.marginL
+	extra := 0.0
+	switch p.Align {
+	case "C":
+		x += (maxW - l.w) / 2
+	case "R":
+		x += maxW - l.w
+	case "J":
+		if !l.last && len(l.words) > 1 {
+			extra = (maxW - l.w) / float64(len(l.words)-1)
+		}
+	}
+	y := doc.currentY()
+	baseline := y + lineH/2 + 0.3*font.Size*mmPerPx
+	for i, w := range l.words {
+		if i > 0 {
+			x += w.space + extra
+		}
+		for _, s := range w.segs {
+			doc.applyFont(s.style.font(font))
+			doc.applyTextColor(s.style.color)
+			doc.pdf.Text(x, baseline, doc.text(s.text))
+			x += s.w
+		}
+	}
+	doc.setY(y + lineH)
+}
+
+func (s textStyle) font(base FontConfig) FontConfig {
+	f := base
+	if s.bold && !strings.Contains(f.Style, "B") {
+		f.Style = "B" + f.Style
+	}
+	if s.italic && !strings.Contains(f.Style, "I") {
+		f.Style += "I"
+	}
+	return f
+}
+
+// parseMarkup splits markup into styled runs. Unclosed markers last to the
+// end of the text.
+func parseMarkup(s string, color Color, theme ThemeConfig) []textSeg {
+	var segs []textSeg
+	var b strings.Builder
+	st := textStyle{color: color}
+	colors := []Color{color}
+	flush := func() {
+		if b.Len() > 0 {
+			segs = append(segs, textSeg{text: b.String(), style: st})
+			b.Reset()
+		}
+	}
+	for i := 0; i < len(s); {
+		switch {
+		case s[i] == '\\' && i+1 < len(s):
+			_, n := utf8.DecodeRuneInString(s[i+1:])
+			b.WriteString(s[i+1 : i+1+n])
+			i += 1 + n
+			continue
+		case strings.HasPrefix(s[i:], "**"):
+			flush()
+			st.bold = !st.bold
+			i += 2
+			continue
+		case s[i] == '*':
+			flush()
+			st.italic = !st.italic
+			i++
+			continue
+		case s[i] == '{':
+			end := strings.IndexByte(s[i:], '}')
+			if end > 0 {
+				name := s[i+1 : i+end]
+				if name == "/" {
+					flush()
+					if len(colors) > 1 {
+						colors = colors[:len(colors)-1]
+					}
+					st.color = colors[len(colors)-1]
+					i += end + 1
+					continue
+				}
+				if c, ok := markupColor(name, theme); ok {
+					flush()
+					colors = append(colors, c)
+					st.color = c
+					i += end + 1
+					continue
+				}
+			}
+		}
+		b.WriteByte(s[i])
+		i++
+	}
+	flush()
+	return segs
+}
+
+func markupColor(name string, theme ThemeConfig) (Color, bool) {
+	switch name {
+	case "primary":
+		return theme.PrimaryText, true
+	case "secondary":
+		return theme.SecondaryText, true
+	case "accent":
+		return theme.AccentColor, true
+	}
+	if strings.HasPrefix(name, "#") {
+		c, err := parseHexColor(name)
+		return c, err == nil
+	}
+	return Color{}, false
+}
+
+// splitWords splits styled runs into words at spaces and line breaks.
+func splitWords(segs []textSeg) []textWord {
+	var words []textWord
+	var cur textWord
+	end := func(br bool) {
+		if len(cur.segs) > 0 {
+			words = append(words, cur)
+		} else if br && len(words) > 0 && !words[len(words)-1].br {
+			words[len(words)-1].br = true
+			return
+		} else if br {
+			// An empty line.
+			words = append(words, textWord{})
+		}
+		if br && len(words) > 0 {
+			words[len(words)-1].br = true
+		}
+		cur = textWord{}
+	}
+	for _, s := range segs {
+		start := 0
+		for i, r := range s.text {
+			if r != ' ' && r != '\n' {
+				continue
+			}
+			if i > start {
+				cur.segs = append(cur.segs, textSeg{text: s.text[start:i], style: s.style})
+			}
+			end(r == '\n')
+			start = i + 1
+		}
+		if start < len(s.text) {
+			cur.segs = append(cur.segs, textSeg{text: s.text[start:], style: s.style})
+		}
+	}
+	end(false)
+	return words
+}
+
+// layoutLines measures words and breaks them into lines of at most maxW.
+// Words wider than a line are broken between characters.
+func layoutLines(doc *Document, words []textWord, font FontConfig, maxW float64) []textLine {
+	for i := range words {
+		w := &words[i]
+		w.w = 0
+		for j := range w.segs {
+			doc.applyFont(w.segs[j].style.font(font))
+			w.segs[j].w = doc.stringWidth(w.segs[j].text)
+			w.w += w.segs[j].w
+		}
+		style := textStyle{}
+		if len(w.segs) > 0 {
+			style = w.segs[0].style
+		}
+		doc.applyFont(style.font(font))
+		w.space = doc.stringWidth(" ")
+	}
+
+	var lines []textLine
+	var cur textLine
+	for _, w := range words {
+		for w.w > maxW && len(w.segs) > 0 {
+			head, tail := breakWord(doc, w, font, maxW)
+			if len(cur.words) > 0 {
+				lines = append(lines, cur)
+				cur = textLine{}
+			}
+			lines = append(lines, textLine{words: []textWord{head}, w: head.w})
+			w = tail
+		}
+		if len(cur.words) > 0 && cur.w+w.space+w.w > maxW {
+			lines = append(lines, cur)
+			cur = textLine{}
+		}
+		if len(cur.words) > 0 {
+			cur.w += w.space
+		}
+		cur.words = append(cur.words, w)
+		cur.w += w.w
+		if w.br {
+			cur.last = true
+			lines = append(lines, cur)
+			cur = textLine{}
+		}
+	}
+	if len(cur.words) > 0 {
+		cur.last = true
+		lines = append(lines, cur)
+	}
+	return lines
+}
+
+// breakWord splits w into a head that fits maxW, at least one character,
+// and the tail.
+func breakWord(doc *Document, w textWord, font FontConfig, maxW float64) (head, tail textWord) {
+	head.space, tail.space, tail.br = w.space, w.space, w.br
+	for i, s := range w.segs {
+		doc.applyFont(s.style.font(font))
+		if head.w+s.w <= maxW {
+			head.segs = append(head.segs, s)
+			head.w += s.w
+			continue
+		}
+		runes := []rune(s.text)
+		n := 0
+		for n < len(runes) && head.w+doc.stringWidth(string(runes[:n+1])) <= maxW {
+			n++
+		}
+		if n == 0 && len(head.segs) == 0 {
+			n = 1
+		}
+		if n > 0 {
+			part := textSeg{text: string(runes[:n]), style: s.style}
+			part.w = doc.stringWidth(part.text)
+			head.segs = append(head.segs, part)
+			head.w += part.w
+		}
+		if n < len(runes) {
+			rest := textSeg{text: string(runes[n:]), style: s.style}
+			rest.w = doc.stringWidth(rest.text)
+			tail.segs = append(tail.segs, rest)
+			tail.w += rest.w
+		}
+		for _, t := range w.segs[i+1:] {
+			tail.segs = append(tail.segs, t)
+			tail.w += t.w
+		}
+		return head, tail
+	}
+	return head, tail
+}
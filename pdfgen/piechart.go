@@ -0,0 +1,208 @@
+package pdfgen
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// PieSlice is one share of a PieChartComponent.
+type PieSlice struct {
+	Label string  // legend label, e.g. "Driving"
+	Value float64 // any non-negative quantity; shares are Value / sum
+	Color Color   // zero value → next color of the chart palette
+}
+
+// PieChartComponent draws a pie or donut chart with an optional legend to its
+// right, e.g. duty-status breakdowns or fuel share per jurisdiction.
+//
+// Legend rows reuse the SectionLabelComponent two-color style:
+// "Driving:" in SectionLabelLeft, " 10:05 (42%)" in SectionLabelValue.
+// The chart is kept together on one page.
+type PieChartComponent struct {
+	Slices      []PieSlice
+	Donut       bool                 // draw a ring instead of a full pie
+	HoleRatio   float64              // donut hole diameter / outer diameter; default 0.55
+	CenterLabel string               // text in the donut hole, e.g. "24:00"; ignored for full pies
+	CenterFont  FontConfig           // zero value → theme default at 11pt bold
+	Diameter    float64              // mm; default 50
+	ShowPercent bool                 // print the percentage on slices of at least 5%
+	ShowLegend  bool                 // list slices to the right of the chart
+	ValueFormat func(float64) string // legend value formatter; nil → percentage only
+	Font        FontConfig           // zero value → theme default at 8pt
+}
+
+// Render draws the chart and advances the Y cursor.
+func (p *PieChartComponent) Render(doc *Document) error {
+	total := 0.0
+	nonZero := 0
+	for _, s := range p.Slices {
+		if s.Value < 0 || math.IsNaN(s.Value) || math.IsInf(s.Value, 0) {
+			return fmt.Errorf("pdfgen: PieChartComponent slice %q has invalid value %v", s.Label, s.Value)
+		}
+		if s.Value > 0 {
+			nonZero++
+		}
+		total += s.Value
+	}
+	if total == 0 {
+		return nil
+	}
+
+	diameter := p.Diameter
+	if diameter == 0 {
+		diameter = 50
+	}
+	holeRatio := p.HoleRatio
+	if holeRatio == 0 {
+		holeRatio = 0.55
+	}
+	font := p.Font
+	if font.Family == "" {
+		font = doc.theme.DefaultFont
+		font.Size = 8
+	}
+	centerFont := p.CenterFont
+	if centerFont.Family == "" {
+		centerFont = FontConfig{Family: doc.theme.DefaultFont.Family, Size: 11, Style: "B"}
+	}
+
+	const legendRowH = 5.5
+	legendH := 0.0
+	if p.ShowLegend {
+		legendH = float64(len(p.Slices)) * legendRowH
+	}
+	blockH := math.Max(diameter, legendH)
+	doc.newPageIfNeeded(blockH)
+
+	startY := doc.currentY()
+	r := diameter / 2
+	cx := doc.marginL + r
+	cy := startY + blockH/2
+	inner := 0.0
+	if p.Donut {
+		inner = r * holeRatio
+	}
+
+	// Slices run clockwise from 12 o'clock.
+	angle := -math.Pi / 2
+	for i, s := range p.Slices {
+		if s.Value == 0 {
+			continue
+		}
+		sweep := s.Value / total * 2 * math.Pi
+		color := seriesColor(doc, s.Color, i)
+		doc.applyColor(color)
+		doc.pdf.Polygon(sectorPoints(cx, cy, inner, r, angle, angle+sweep), "F")
+
+		share := s.Value / total
+		if p.ShowPercent && share >= 0.05 {
+			mid := angle + sweep/2
+			lr := r * 0.62
+			if p.Donut {
+				lr = (r + inner) / 2
+			}
+			doc.applyFont(font)
+			doc.applyTextColor(contrastText(doc, color))
+			doc.pdf.SetXY(cx+lr*math.Cos(mid)-6, cy+lr*math.Sin(mid)-2)
+			doc.cellFormat(12, 4, formatPercent(share), "", 0, "C", false)
+		}
+		angle += sweep
+	}
+
+	// Thin white separators keep adjacent slices of similar color apart.
+	if nonZero > 1 {
+		doc.applyColor(Color{R: 255, G: 255, B: 255})
+		angle = -math.Pi / 2
+		for _, s := range p.Slices {
+			if s.Value == 0 {
+				continue
+			}
+			doc.pdf.Line(cx+inner*math.Cos(angle), cy+inner*math.Sin(angle), cx+r*math.Cos(angle), cy+r*math.Sin(angle))
+			angle += s.Value / total * 2 * math.Pi
+		}
+	}
+
+	if p.Donut && p.CenterLabel != "" {
+		doc.applyFont(centerFont)
+		doc.applyTextColor(doc.theme.PrimaryText)
+		doc.pdf.SetXY(cx-inner, cy-3)
+		doc.cellFormat(2*inner, 6, truncateText(doc, p.CenterLabel, 2*inner-2*doc.pdf.GetCellMargin()), "", 0, "C", false)
+	}
+
+	if p.ShowLegend {
+		p.renderLegend(doc, doc.marginL+diameter+8, cy-legendH/2, legendRowH, total, font)
+	}
+
+	doc.setY(startY + blockH + 2)
+	return nil
+}
+
+// renderLegend lists the slices as swatch + "Label:" + " value (pct)" rows.
+func (p *PieChartComponent) renderLegend(doc *Document, x, y, rowH, total float64, font FontConfig) {
+	cm := doc.pdf.GetCellMargin()
+	maxW := doc.marginL + doc.usableWidth() - x
+	for i, s := range p.Slices {
+		rowY := y + float64(i)*rowH
+		doc.applyColor(seriesColor(doc, s.Color, i))
+		doc.pdf.Rect(x, rowY+(rowH-legendSwatch)/2, legendSwatch, legendSwatch, "F")
+
+		label := s.Label + ":"
+		value := " " + formatPercent(s.Value/total)
+		if p.ValueFormat != nil {
+			value = " " + p.ValueFormat(s.Value) + " (" + formatPercent(s.Value/total) + ")"
+		}
+
+		tx := x + legendSwatch + legendGap
+		doc.applyFont(font)
+		labelW := doc.stringWidth(label) + 2*cm
+		doc.applyTextColor(doc.theme.SectionLabelLeft)
+		doc.pdf.SetXY(tx, rowY)
+		doc.cellFormat(labelW, rowH, label, "", 0, "L", false)
+
+		valueW := maxW - (tx - x) - labelW
+		doc.applyTextColor(doc.theme.SectionLabelValue)
+		doc.pdf.SetXY(tx+labelW-cm, rowY)
+		doc.cellFormat(valueW, rowH, truncateText(doc, value, valueW-2*cm), "", 0, "L", false)
+	}
+}
+
+// sectorPoints approximates the annular sector between radii inner and outer
+// and angles a0..a1 (radians) as a polygon. inner == 0 yields a pie wedge.
+func sectorPoints(cx, cy, inner, outer, a0, a1 float64) []fpdf.PointType {
+	steps := int(math.Ceil((a1 - a0) / (math.Pi / 90))) // ≤ 2° per segment
+	if steps < 1 {
+		steps = 1
+	}
+	pts := make([]fpdf.PointType, 0, 2*steps+3)
+	for k := 0; k <= steps; k++ {
+		a := a0 + (a1-a0)*float64(k)/float64(steps)
+		pts = append(pts, fpdf.PointType{X: cx + outer*math.Cos(a), Y: cy + outer*math.Sin(a)})
+	}
+	if inner == 0 {
+		return append(pts, fpdf.PointType{X: cx, Y: cy})
+	}
+	for k := steps; k >= 0; k-- {
+		a := a0 + (a1-a0)*float64(k)/float64(steps)
+		pts = append(pts, fpdf.PointType{X: cx + inner*math.Cos(a), Y: cy + inner*math.Sin(a)})
+	}
+	return pts
+}
+
+// contrastText returns white for dark fills and the theme text color otherwise.
+func contrastText(doc *Document, fill Color) Color {
+	luminance := 0.299*float64(fill.R) + 0.587*float64(fill.G) + 0.114*float64(fill.B)
+	if luminance < 150 {
+		return Color{R: 255, G: 255, B: 255}
+	}
+	return doc.theme.PrimaryText
+}
+
+// formatPercent formats a 0..1 share as a whole percentage, or "<1%".
+func formatPercent(share float64) string {
+	if share > 0 && share < 0.005 {
+		return "<1%"
+	}
+	return fmt.Sprintf("%.0f%%", share*100)
+}
@@ -0,0 +1,94 @@
+package pdfgen
+
+import (
+	"maps"
+	"regexp"
+	"strconv"
+)
+
+// pageInfo records what a page's header and footer render with. Headers
+// and footers are laid out as pages open but their text is drawn by finish,
+// once the page count is known, so {total} is exact and aligned like any
+// other text.
+type pageInfo struct {
+	header *PageHeaderComponent // variant drawn on the page; nil for none
+	footer *FooterComponent     // footer registered when the page closed
+	sec    *section             // page geometry
+	vars   map[string]string    // variables when the page closed
+	closed bool
+}
+
+// placeholder matches {name} in header and footer text.
+var placeholder = regexp.MustCompile(`\{([A-Za-z0-9_]+)\}`)
+
+// SetVar sets the variable {name} of page headers and footers. A page uses
+// the values set when it ends, so SetVar before adding a section labels the
+// pages the section ends on, e.g. doc.SetVar("driver", "J. Smith").
+func (d *Document) SetVar(name, value string) *Document {
+	// Copy so pages already closed keep their values.
+	vars := maps.Clone(d.vars)
+	if vars == nil {
+		vars = make(map[string]string)
+	}
+	vars[name] = value
+	d.vars = vars
+	return d
+}
+
+// expandPlaceholders replaces {page}, {total}, {date}, {report_id} and the
+// variables in s. total 0 leaves {total} for fpdf to replace at output.
+// Unknown placeholders are kept as-is.
+func (d *Document) expandPlaceholders(s string, page, total int, vars map[string]string) string {
+	return placeholder.ReplaceAllStringFunc(s, func(m string) string {
+		name := m[1 : len(m)-1]
+		switch name {
+		case "page":
+			return strconv.Itoa(page)
+		case "total":
+			if total > 0 {
+				return strconv.Itoa(total)
+			}
+			return m
+		case "date":
+			return d.date
+		case "report_id":
+			return d.reportID
+		}
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return m
+	})
+}
+
+// page returns the record of page n, growing the list as pages open.
+func (d *Document) page(n int) *pageInfo {
+	for len(d.pages) < n {
+		d.pages = append(d.pages, pageInfo{})
+	}
+	return &d.pages[n-1]
+}
+
+// drawPageText draws the header and footer text of every page with the
+// printed page numbers and totals from numberPages.
+func (d *Document) drawPageText(numbers, totals []int) {
+	for n := 1; n <= d.pdf.PageCount(); n++ {
+		info := d.page(n)
+		footer, vars := info.footer, info.vars
+		if !info.closed {
+			footer, vars = d.footer, d.vars
+		}
+		if info.header == nil && footer == nil {
+			continue
+		}
+		d.setPage(n)
+		page, total := numbers[n-1], totals[n-1]
+		if info.header != nil {
+			d.pdf.SetY(d.marginT)
+			info.header.drawText(d, page, total, vars)
+		}
+		if footer != nil {
+			footer.forPage(page).draw(d, page, total, vars)
+		}
+	}
+}
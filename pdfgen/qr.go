@@ -0,0 +1,485 @@
+package pdfgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// QRLevel is the error correction level of a QR code: how much of the
+// symbol can be damaged or covered and still scan.
+type QRLevel int
+
+const (
+	QRLevelM QRLevel = iota // ~15% recoverable (default)
+	QRLevelL                // ~7%
+	QRLevelQ                // ~25%
+	QRLevelH                // ~30%; use when a logo covers the center
+)
+
+// qrTables holds, per level and version 1–40, the error correction
+// codewords per block and the number of blocks (ISO/IEC 18004 table 9).
+var qrTables = map[QRLevel][2][41]int{
+	QRLevelL: {
+		{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	},
+	QRLevelM: {
+		{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+		{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	},
+	QRLevelQ: {
+		{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	},
+	QRLevelH: {
+		{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+	},
+}
+
+// qrFormatLevel is the level's value in the format information.
+var qrFormatLevel = map[QRLevel]int{QRLevelL: 1, QRLevelM: 0, QRLevelQ: 3, QRLevelH: 2}
+
+const qrAlphanumeric = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
+
+// qrCode is an encoded QR symbol without its quiet zone; dark[y][x] is the
+// module in row y, column x.
+type qrCode struct {
+	size int
+	dark [][]bool
+	fn   [][]bool // function patterns, which masks leave alone
+}
+
+// encodeQR encodes data in the smallest version that holds it at level,
+// in numeric, alphanumeric or byte mode, whichever is the most compact for
+// all of data.
+func encodeQR(data string, level QRLevel) (*qrCode, error) {
+	tables, ok := qrTables[level]
+	if !ok {
+		return nil, fmt.Errorf("unknown QR level %d", level)
+	}
+
+	mode, countBits := 4, [3]int{8, 16, 16}
+	switch {
+	case data != "" && strings.Trim(data, "0123456789") == "":
+		mode, countBits = 1, [3]int{10, 12, 14}
+	case data != "" && strings.Trim(data, qrAlphanumeric) == "":
+		mode, countBits = 2, [3]int{9, 11, 13}
+	}
+	var payload qrBits
+	count := len(data)
+	switch mode {
+	case 1:
+		for i := 0; i < len(data); i += 3 {
+			group := data[i:min(i+3, len(data))]
+			n := 0
+			for _, c := range group {
+				n = n*10 + int(c-'0')
+			}
+			payload.put(n, len(group)*3+1)
+		}
+	case 2:
+		for i := 0; i < len(data); i += 2 {
+			if i+1 < len(data) {
+				payload.put(strings.IndexByte(qrAlphanumeric, data[i])*45+strings.IndexByte(qrAlphanumeric, data[i+1]), 11)
+			} else {
+				payload.put(strings.IndexByte(qrAlphanumeric, data[i]), 6)
+			}
+		}
+	default:
+		for i := 0; i < len(data); i++ {
+			payload.put(int(data[i]), 8)
+		}
+	}
+
+	version, capacity := 0, 0
+	var bits qrBits
+	for v := 1; v <= 40; v++ {
+		capacity = (qrRawModules(v)/8 - tables[0][v]*tables[1][v]) * 8
+		cb := countBits[0]
+		if v >= 27 {
+			cb = countBits[2]
+		} else if v >= 10 {
+			cb = countBits[1]
+		}
+		if count >= 1<<cb || 4+cb+len(payload) > capacity {
+			continue
+		}
+		version = v
+		bits.put(mode, 4)
+		bits.put(count, cb)
+		bits = append(bits, payload...)
+		break
+	}
+	if version == 0 {
+		return nil, fmt.Errorf("%d bytes is too long for a QR code at this level", len(data))
+	}
+
+	// Terminator, byte alignment and pad codewords.
+	bits.put(0, min(4, capacity-len(bits)))
+	bits.put(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.put(pad, 8)
+	}
+	codewords := make([]byte, len(bits)/8)
+	for i, b := range bits {
+		codewords[i/8] |= byte(b) << (7 - i%8)
+	}
+
+	q := newQRCode(version)
+	q.place(qrInterleave(codewords, version, tables[0][version], tables[1][version]))
+	best, bestPenalty := 0, -1
+	for mask := 0; mask < 8; mask++ {
+		q.applyMask(mask)
+		q.drawFormat(level, mask)
+		if p := q.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		q.applyMask(mask) // XOR again to undo
+	}
+	q.applyMask(best)
+	q.drawFormat(level, best)
+	return q, nil
+}
+
+// qrBits is a bit stream, one bit per element.
+type qrBits []int
+
+// put appends the low n bits of v, most significant first.
+func (b *qrBits) put(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, v>>i&1)
+	}
+}
+
+// qrRawModules returns the number of modules of a version that hold data
+// and error correction codewords, remainder bits included.
+func qrRawModules(version int) int {
+	n := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		n -= (25*align-10)*align - 55
+		if version >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+// qrInterleave splits data into blocks, appends each block's Reed-Solomon
+// codewords and interleaves the blocks.
+func qrInterleave(data []byte, version, eccLen, numBlocks int) []byte {
+	raw := qrRawModules(version) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+	gen := rsGenerator(eccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		block := append([]byte(nil), data[k:k+n]...)
+		k += n
+		ecc := rsRemainder(block, gen)
+		if i < numShort {
+			// Short blocks skip the last data column.
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, ecc...)
+	}
+	out := make([]byte, 0, raw)
+	for i := 0; i < len(blocks[0]); i++ {
+		for j, block := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				out = append(out, block[i])
+			}
+		}
+	}
+	return out
+}
+
+// gfMul multiplies in GF(2^8) with the QR polynomial x^8+x^4+x^3+x^2+1.
+func gfMul(x, y byte) byte {
+	var z byte
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x1D
+		z ^= (y >> i & 1) * x
+	}
+	return z
+}
+
+// rsGenerator returns the coefficients, highest power first and the
+// leading 1 left out, of the Reed-Solomon generator of the given degree.
+func rsGenerator(degree int) []byte {
+	g := make([]byte, degree)
+	g[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range g {
+			g[j] = gfMul(g[j], root)
+			if j+1 < len(g) {
+				g[j] ^= g[j+1]
+			}
+		}
+		root = gfMul(root, 0x02)
+	}
+	return g
+}
+
+// rsRemainder returns the Reed-Solomon error correction codewords of data.
+func rsRemainder(data, gen []byte) []byte {
+	r := make([]byte, len(gen))
+	for _, b := range data {
+		f := b ^ r[0]
+		copy(r, r[1:])
+		r[len(r)-1] = 0
+		for i := range r {
+			r[i] ^= gfMul(gen[i], f)
+		}
+	}
+	return r
+}
+
+// newQRCode returns a symbol of the version with its function patterns
+// drawn and the format areas reserved.
+func newQRCode(version int) *qrCode {
+	size := version*4 + 17
+	q := &qrCode{size: size, dark: make([][]bool, size), fn: make([][]bool, size)}
+	for i := range q.dark {
+		q.dark[i] = make([]bool, size)
+		q.fn[i] = make([]bool, size)
+	}
+	for i := 0; i < size; i++ {
+		q.set(6, i, i%2 == 0)
+		q.set(i, 6, i%2 == 0)
+	}
+	for _, c := range [][2]int{{3, 3}, {size - 4, 3}, {3, size - 4}} {
+		for dy := -4; dy <= 4; dy++ {
+			for dx := -4; dx <= 4; dx++ {
+				x, y := c[0]+dx, c[1]+dy
+				if x >= 0 && x < size && y >= 0 && y < size {
+					d := max(abs(dx), abs(dy))
+					q.set(x, y, d != 2 && d != 4)
+				}
+			}
+		}
+	}
+	pos := qrAlignment(version)
+	for i, cy := range pos {
+		for j, cx := range pos {
+			if (i == 0 && j == 0) || (i == 0 && j == len(pos)-1) || (i == len(pos)-1 && j == 0) {
+				continue // finder corners
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					q.set(cx+dx, cy+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+	q.drawFormat(QRLevelM, 0) // reserve; redrawn with the mask
+	if version >= 7 {
+		rem := version
+		for i := 0; i < 12; i++ {
+			rem = rem<<1 ^ (rem>>11)*0x1F25
+		}
+		bits := version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := bits>>i&1 == 1
+			a, b := size-11+i%3, i/3
+			q.set(a, b, dark)
+			q.set(b, a, dark)
+		}
+	}
+	return q
+}
+
+// qrAlignment returns the row and column centers of the alignment patterns.
+func qrAlignment(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	n := version/7 + 2
+	step := (version*8 + n*3 + 5) / (n*4 - 4) * 2
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, version*4+17-7; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+// set draws a function module at column x, row y.
+func (q *qrCode) set(x, y int, dark bool) {
+	q.dark[y][x] = dark
+	q.fn[y][x] = true
+}
+
+// drawFormat draws both copies of the format information and the dark
+// module.
+func (q *qrCode) drawFormat(level QRLevel, mask int) {
+	data := qrFormatLevel[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+	for i := 0; i <= 5; i++ {
+		q.set(8, i, bit(i))
+	}
+	q.set(8, 7, bit(6))
+	q.set(8, 8, bit(7))
+	q.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		q.set(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		q.set(q.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		q.set(8, q.size-15+i, bit(i))
+	}
+	q.set(8, q.size-8, true)
+}
+
+// place fills the data modules with codewords in the zigzag order of
+// column pairs, right to left.
+func (q *qrCode) place(codewords []byte) {
+	i := 0
+	for right := q.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		up := (right+1)&2 == 0
+		for vert := 0; vert < q.size; vert++ {
+			y := vert
+			if up {
+				y = q.size - 1 - vert
+			}
+			for j := 0; j < 2; j++ {
+				x := right - j
+				if q.fn[y][x] {
+					continue
+				}
+				if i < len(codewords)*8 {
+					q.dark[y][x] = codewords[i/8]>>(7-i%8)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask XORs the data modules with mask pattern mask.
+func (q *qrCode) applyMask(mask int) {
+	for y := 0; y < q.size; y++ {
+		for x := 0; x < q.size; x++ {
+			var flip bool
+			switch mask {
+			case 0:
+				flip = (x+y)%2 == 0
+			case 1:
+				flip = y%2 == 0
+			case 2:
+				flip = x%3 == 0
+			case 3:
+				flip = (x+y)%3 == 0
+			case 4:
+				flip = (x/3+y/2)%2 == 0
+			case 5:
+				flip = x*y%2+x*y%3 == 0
+			case 6:
+				flip = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				flip = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if flip && !q.fn[y][x] {
+				q.dark[y][x] = !q.dark[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the symbol by the four mask evaluation rules; the mask
+// with the lowest score is used.
+func (q *qrCode) penalty() int {
+	n := q.size
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			return q.dark[x][y]
+		}
+		return q.dark[y][x]
+	}
+	p := 0
+	finder := []bool{true, false, true, true, true, false, true}
+	for _, tr := range []bool{false, true} {
+		for y := 0; y < n; y++ {
+			// Runs of five or more modules of one color.
+			run := 1
+			for x := 1; x <= n; x++ {
+				if x < n && at(x, y, tr) == at(x-1, y, tr) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					p += 3 + run - 5
+				}
+				run = 1
+			}
+			// Finder-like 1:1:3:1:1 patterns with four light modules
+			// on either side.
+			for x := 0; x+7 <= n; x++ {
+				match := true
+				for k, d := range finder {
+					if at(x+k, y, tr) != d {
+						match = false
+						break
+					}
+				}
+				if match && (q.light(x-4, x, y, tr) || q.light(x+7, x+11, y, tr)) {
+					p += 40
+				}
+			}
+		}
+	}
+	dark := 0
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			if q.dark[y][x] {
+				dark++
+			}
+			if x+1 < n && y+1 < n {
+				c := q.dark[y][x]
+				if q.dark[y][x+1] == c && q.dark[y+1][x] == c && q.dark[y+1][x+1] == c {
+					p += 3
+				}
+			}
+		}
+	}
+	// Deviation of the dark share from 50%, in 5% steps.
+	p += abs(dark*20-n*n*10) / (n * n) * 10
+	return p
+}
+
+// light reports whether modules from through to-1 of line y are light,
+// counting modules outside the symbol as light.
+func (q *qrCode) light(from, to, y int, transpose bool) bool {
+	for x := from; x < to; x++ {
+		if x < 0 || x >= q.size {
+			continue
+		}
+		if (transpose && q.dark[x][y]) || (!transpose && q.dark[y][x]) {
+			return false
+		}
+	}
+	return true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,215 @@
+package pdfgen
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRSRemainder(t *testing.T) {
+	// Version 1-M symbols: ISO/IEC 18004 annex I ("01234567") and the
+	// widely published "HELLO WORLD" example.
+	tests := []struct {
+		name string
+		data []byte
+		want []byte
+	}{
+		{
+			name: "01234567",
+			data: []byte{0x10, 0x20, 0x0C, 0x56, 0x61, 0x80, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11, 0xEC, 0x11},
+			want: []byte{0xA5, 0x24, 0xD4, 0xC1, 0xED, 0x36, 0xC7, 0x87, 0x2C, 0x55},
+		},
+		{
+			name: "HELLO WORLD",
+			data: []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17},
+			want: []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23},
+		},
+	}
+	for _, tt := range tests {
+		if got := rsRemainder(tt.data, rsGenerator(len(tt.want))); !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: error correction % X, want % X", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeQR(t *testing.T) {
+	// Module matrices, without the quiet zone, match an independent QR
+	// encoder, mask choice included; # is dark.
+	tests := []struct {
+		name  string
+		data  string
+		level QRLevel
+		want  []string
+	}{
+		{
+			name:  "L HELLO WORLD",
+			data:  "HELLO WORLD",
+			level: QRLevelL,
+			want: []string{
+				"#######...#.#.#######",
+				"#.....#.....#.#.....#",
+				"#.###.#.#.#...#.###.#",
+				"#.###.#.....#.#.###.#",
+				"#.###.#..#.##.#.###.#",
+				"#.....#..###..#.....#",
+				"#######.#.#.#.#######",
+				"........#.#..........",
+				"###.#####.#.###...#..",
+				"###.##..#.##....#...#",
+				"###.#.##.###..#.##...",
+				"#..##..#.#.###.#.###.",
+				"...#####.###..###.#.#",
+				"........#.#...#...#.#",
+				"#######.#...#..#.##..",
+				"#.....#.#.#...##.#...",
+				"#.###.#.##..#.#######",
+				"#.###.#...##.#.#...#.",
+				"#.###.#.#.##.###.#..#",
+				"#.....#.#..###...#.##",
+				"#######.#.##.###....#",
+			},
+		},
+		{
+			name:  "M 01234567",
+			data:  "01234567",
+			level: QRLevelM,
+			want: []string{
+				"#######..#.##.#######",
+				"#.....#..####.#.....#",
+				"#.###.#.#.....#.###.#",
+				"#.###.#.##....#.###.#",
+				"#.###.#.#.###.#.###.#",
+				"#.....#.#...#.#.....#",
+				"#######.#.#.#.#######",
+				"........#..##........",
+				"#.#####..#..#.#####..",
+				"...#.#.##.#.#..#.##..",
+				"..#...##.#.#.#..#####",
+				"....#....#.....####..",
+				"...######..#.#..#....",
+				"........#.#####..##..",
+				"#######..##.#.##.....",
+				"#.....#.#.#####...#.#",
+				"#.###.#.#...#..#.##..",
+				"#.###.#.##..#..#.....",
+				"#.###.#.#.##.#..#.#..",
+				"#.....#........##.##.",
+				"#######.####.#..#.#..",
+			},
+		},
+		{
+			name:  "Q HELLO WORLD",
+			data:  "HELLO WORLD",
+			level: QRLevelQ,
+			want: []string{
+				"#######.##....#######",
+				"#.....#.#..#..#.....#",
+				"#.###.#.#..##.#.###.#",
+				"#.###.#.#.....#.###.#",
+				"#.###.#.#.#...#.###.#",
+				"#.....#...#...#.....#",
+				"#######.#.#.#.#######",
+				"........#............",
+				".##.#.##....#.#.#####",
+				".#......####....#...#",
+				"..##.###.##...#.##...",
+				".##.##.#..##.#.#.###.",
+				"#...#.#.#.###.###.#.#",
+				"........##.#..#...#.#",
+				"#######.#.#....#.##..",
+				"#.....#..#.##.##.#...",
+				"#.###.#.#.#...#######",
+				"#.###.#..#.#.#.#...#.",
+				"#.###.#.#..#.###.#..#",
+				"#.....#.#.####...#.##",
+				"#######....#.###....#",
+			},
+		},
+		{
+			name:  "H HELLO WORLD",
+			data:  "HELLO WORLD",
+			level: QRLevelH,
+			want: []string{
+				"#######.###.##.##.#######",
+				"#.....#...#.##.##.#.....#",
+				"#.###.#.#.#.....#.#.###.#",
+				"#.###.#..##..####.#.###.#",
+				"#.###.#.##..#.##..#.###.#",
+				"#.....#..#.....#..#.....#",
+				"#######.#.#.#.#.#.#######",
+				"........#.#.##..#........",
+				".....##...####.#..#.#.#.#",
+				"##.##..####...#.#.##.#..#",
+				"....#.#....#...#..#.#....",
+				"#.###..#.#.#...#.####..#.",
+				"#.#.###..###.....####.#.#",
+				"###.##..#....#...##..#.#.",
+				"#...#.##....#.####....#..",
+				"#..##....#.##.#.#..##.#.#",
+				"#.#.#.#..#...#.########.#",
+				"........##..#.#.#...####.",
+				"#######...##.####.#.#.##.",
+				"#.....#.#..#.####...#####",
+				"#.###.#......##.######.##",
+				"#.###.#...###....#.#.##.#",
+				"#.###.#..#.#..#.#.#..#..#",
+				"#.....#.....####..#..##..",
+				"#######...###..##.#.#.###",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := encodeQR(tt.data, tt.level)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q.size != len(tt.want) {
+				t.Fatalf("size %d, want %d", q.size, len(tt.want))
+			}
+			for y, row := range tt.want {
+				var sb strings.Builder
+				for x := 0; x < q.size; x++ {
+					if q.dark[y][x] {
+						sb.WriteByte('#')
+					} else {
+						sb.WriteByte('.')
+					}
+				}
+				if got := sb.String(); got != row {
+					t.Errorf("row %2d %s, want %s", y, got, row)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodeQRVersions(t *testing.T) {
+	// Capacities at the version boundaries of ISO/IEC 18004 table 7.
+	tests := []struct {
+		data    string
+		level   QRLevel
+		version int
+	}{
+		{strings.Repeat("1", 41), QRLevelL, 1},
+		{strings.Repeat("1", 42), QRLevelL, 2},
+		{strings.Repeat("A", 20), QRLevelM, 1},
+		{strings.Repeat("A", 21), QRLevelM, 2},
+		{strings.Repeat("a", 7), QRLevelH, 1},
+		{strings.Repeat("a", 8), QRLevelH, 2},
+		{strings.Repeat("a", 2953), QRLevelL, 40},
+	}
+	for _, tt := range tests {
+		q, err := encodeQR(tt.data, tt.level)
+		if err != nil {
+			t.Errorf("%d × %q: %v", len(tt.data), tt.data[0], err)
+			continue
+		}
+		if got := (q.size - 17) / 4; got != tt.version {
+			t.Errorf("%d × %q at level %d: version %d, want %d", len(tt.data), tt.data[0], tt.level, got, tt.version)
+		}
+	}
+	if _, err := encodeQR(strings.Repeat("a", 2954), QRLevelL); err == nil {
+		t.Error("2954 bytes at level L: want error")
+	}
+}
@@ -0,0 +1,120 @@
+package pdfgen
+
+import "fmt"
+
+// QRCodeComponent renders a QR code encoded by pdfgen itself, drawn as
+// vector modules, e.g. a link to the portal's verification page of the
+// report. It sits in the content flow, or floats at a page corner like
+// LogoComponent when Position is set.
+type QRCodeComponent struct {
+	Data        string
+	Level       QRLevel    // error correction; default QRLevelM
+	Size        float64    // mm, symbol width quiet zone included; default 25
+	QuietZone   int        // light modules around the symbol; 0 → 4, negative → none
+	Color       Color      // modules; zero value → black
+	Caption     string     // text centered under the symbol, e.g. "Scan to verify"
+	CaptionFont FontConfig // zero value → theme DefaultFont at 7pt
+	Align       string     // in the flow: "L" | "C" | "R"; default "L"
+	Position    string     // "" in the flow; "top-left" | "top-right" | "top-center" floats
+	OffsetX     float64    // additional X offset in mm
+	OffsetY     float64    // additional Y offset in mm
+}
+
+// Render draws the QR code. In the flow it moves to a new page when it does
+// not fit and advances Y below the caption; floating, Y is not advanced.
+func (q *QRCodeComponent) Render(doc *Document) error {
+	if q.Data == "" {
+		return fmt.Errorf("pdfgen: QRCodeComponent requires Data")
+	}
+	code, err := encodeQR(q.Data, q.Level)
+	if err != nil {
+		return fmt.Errorf("pdfgen: QRCodeComponent: %w", err)
+	}
+	size := q.Size
+	if size == 0 {
+		size = 25
+	}
+	quiet := q.QuietZone
+	if quiet == 0 {
+		quiet = 4
+	}
+	quiet = max(quiet, 0)
+	m := size / float64(code.size+2*quiet)
+
+	x, y, caption := placeSymbol(doc, q.Position, q.Align, q.OffsetX, q.OffsetY, size, size, q.Caption, q.CaptionFont)
+	doc.applyColor(q.Color)
+	for row := 0; row < code.size; row++ {
+		// One rectangle per run of dark modules.
+		for col := 0; col < code.size; {
+			if !code.dark[row][col] {
+				col++
+				continue
+			}
+			run := 1
+			for col+run < code.size && code.dark[row][col+run] {
+				run++
+			}
+			doc.pdf.Rect(x+float64(quiet+col)*m, y+float64(quiet+row)*m, float64(run)*m, m, "F")
+			col += run
+		}
+	}
+	caption()
+	return nil
+}
+
+// placeSymbol positions a w×h code with its caption, in the flow or
+// floating as LogoComponent does, and returns its top-left corner and a
+// func that draws the caption and, in the flow, advances Y past it.
+func placeSymbol(doc *Document, position, align string, offX, offY, w, h float64, caption string, font FontConfig) (x, y float64, done func()) {
+	if font.Family == "" {
+		font.Family = doc.theme.DefaultFont.Family
+	}
+	if font.Size == 0 {
+		font.Size = 7
+	}
+	captionH := 0.0
+	if caption != "" {
+		captionH = 1 + font.Size*0.5
+	}
+
+	pageW, _ := doc.pdf.GetPageSize()
+	flow := position == ""
+	if flow {
+		doc.newPageIfNeeded(h + captionH)
+		x = doc.marginL
+		switch align {
+		case "C":
+			x += (doc.usableWidth() - w) / 2
+		case "R":
+			x += doc.usableWidth() - w
+		}
+		x += offX
+		y = doc.currentY() + offY
+	} else {
+		switch position {
+		case "top-right":
+			x = pageW - doc.marginR - w + offX
+		case "top-center":
+			x = (pageW-w)/2 + offX
+		default: // "top-left"
+			x = doc.marginL + offX
+		}
+		y = doc.marginT + offY
+	}
+
+	savedY := doc.currentY()
+	return x, y, func() {
+		if caption != "" {
+			doc.applyFont(font)
+			doc.applyTextColor(doc.theme.PrimaryText)
+			cw := max(w, doc.stringWidth(caption)+2*doc.pdf.GetCellMargin())
+			doc.pdf.SetXY(x+(w-cw)/2, y+h+1)
+			doc.cellFormat(cw, font.Size*0.5, caption, "", 0, "C", false)
+		}
+		if flow {
+			doc.setY(y + h + captionH)
+		} else {
+			doc.setY(savedY)
+		}
+	}
+}
@@ -0,0 +1,481 @@
+package pdfgen
+
+import (
+	_ "embed"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// MapProjection maps latitude/longitude onto the flat page.
+type MapProjection int
+
+const (
+	ProjectionMercator        MapProjection = iota // Web Mercator; true shapes, as on web maps
+	ProjectionEquirectangular                      // lat/lng grid scaled by the cosine of the center latitude
+)
+
+// GeoPoint is one GPS fix of a route. A NaN Lat or Lng breaks the line, e.g.
+// across a GPS outage.
+type GeoPoint struct {
+	Lat, Lng float64 // decimal degrees
+	Speed    float64 // in SpeedUnit; selects the segment's SpeedBand, NaN → the first
+}
+
+// RouteStop is a numbered pin on a RouteMapComponent, e.g. one row of the
+// movement table.
+type RouteStop struct {
+	Lat, Lng float64
+	Label    string // pin text; "" → 1-based position in Stops
+}
+
+// SpeedBand colors the route segments whose speed is at most Max.
+type SpeedBand struct {
+	Max   float64 // inclusive upper bound; the last band also takes faster segments
+	Color Color
+	Label string // legend text; "" → range from the neighboring bounds, e.g. "25–55 mph"
+}
+
+// defaultSpeedBands color idling, city, highway and speeding segments.
+func defaultSpeedBands(theme ThemeConfig) []SpeedBand {
+	return []SpeedBand{
+		{Max: 5, Color: theme.AccentColor},                     // #94A3B8 slate-400
+		{Max: 35, Color: Color{R: 245, G: 158, B: 11}},         // #F59E0B amber-500
+		{Max: 70, Color: Color{R: 59, G: 130, B: 246}},         // #3B82F6 blue-500
+		{Max: math.Inf(1), Color: Color{R: 239, G: 68, B: 68}}, // #EF4444 red-500
+	}
+}
+
+// Route marker colors.
+var (
+	routeStartColor = Color{R: 16, G: 185, B: 129} // #10B981 emerald-500
+	routeEndColor   = Color{R: 239, G: 68, B: 68}  // #EF4444 red-500
+)
+
+// RouteMapComponent draws a vehicle route from GPS points as a vector map:
+// the polyline colored by speed, start and end markers, numbered stop pins
+// and a scale bar, optionally over generalized US state boundaries bundled
+// with pdfgen. Nothing is fetched from a tile server, so it renders offline.
+//
+// The view fits the points and stops into the box, keeping their aspect
+// ratio. The map is kept together on one page.
+type RouteMapComponent struct {
+	Points          []GeoPoint
+	Stops           []RouteStop
+	Projection      MapProjection // default ProjectionMercator
+	SpeedBands      []SpeedBand   // ascending Max; nil → 0–5 slate, 5–35 amber, 35–70 blue, faster red
+	SpeedUnit       string        // legend unit of the default band labels; default "mph"
+	Color           Color         // nonzero → whole route in this color, speeds ignored
+	StopColor       Color         // pins; zero value → theme SectionLabelLeft
+	StateBoundaries bool          // draw the bundled US state outlines
+	BoundaryColor   Color         // zero value → theme AccentColor
+	ShowScale       bool          // draw a scale bar in the bottom-left corner
+	ScaleUnit       DistanceUnit  // scale bar unit; Meters switch to km from 1,000 m
+	ShowLegend      bool          // draw the speed legend above the map
+	Width           float64       // mm; 0 = full usable width
+	Height          float64       // mm map height; default 90
+	Padding         float64       // mm between the route and the frame; default 6
+	LineWidth       float64       // mm; default 0.8
+	Font            FontConfig    // zero value → theme default at 7pt
+}
+
+// Render draws the map and advances the Y cursor.
+func (m *RouteMapComponent) Render(doc *Document) error {
+	// Lat/lng bounds of everything that must be in view.
+	latMin, latMax := math.Inf(1), math.Inf(-1)
+	lngMin, lngMax := math.Inf(1), math.Inf(-1)
+	extend := func(lat, lng float64) {
+		if validLatLng(lat, lng) {
+			latMin, latMax = math.Min(latMin, lat), math.Max(latMax, lat)
+			lngMin, lngMax = math.Min(lngMin, lng), math.Max(lngMax, lng)
+		}
+	}
+	for _, p := range m.Points {
+		extend(p.Lat, p.Lng)
+	}
+	for _, s := range m.Stops {
+		extend(s.Lat, s.Lng)
+	}
+	if math.IsInf(latMin, 1) {
+		return nil
+	}
+
+	font := m.Font
+	if font.Family == "" {
+		font = doc.theme.DefaultFont
+		font.Size = 7
+	}
+	width := m.Width
+	if width == 0 {
+		width = doc.usableWidth()
+	}
+	height := m.Height
+	if height == 0 {
+		height = 90
+	}
+	pad := m.Padding
+	if pad == 0 {
+		pad = 6
+	}
+	lineW := m.LineWidth
+	if lineW == 0 {
+		lineW = 0.8
+	}
+	bands := m.SpeedBands
+	if len(bands) == 0 {
+		bands = defaultSpeedBands(doc.theme)
+	}
+	unit := m.SpeedUnit
+	if unit == "" {
+		unit = "mph"
+	}
+
+	var items []legendItem
+	legendH := 0.0
+	if m.ShowLegend {
+		if m.Color == (Color{}) {
+			for i, b := range bands {
+				items = append(items, legendItem{Label: speedBandLabel(bands, i, unit), Color: b.Color})
+			}
+		}
+		items = append(items, legendItem{Label: "Start", Color: routeStartColor}, legendItem{Label: "End", Color: routeEndColor})
+		_, _, legendH = legendLayout(doc, items, width, font)
+		legendH += 2
+	}
+	doc.newPageIfNeeded(legendH + height)
+
+	startY := doc.currentY()
+	if len(items) > 0 {
+		drawLegend(doc, items, doc.marginL, startY, width, font)
+	}
+	mapX, mapY := doc.marginL, startY+legendH
+
+	// ── Projection ───────────────────────────────────────────────────────────
+	proj := newGeoProjection(m.Projection, (latMin+latMax)/2)
+	x0, y0 := proj.xy(latMin, lngMin)
+	x1, y1 := proj.xy(latMax, lngMax)
+	// Zoom no further than 2 km across, e.g. for a vehicle that never moved.
+	minSpan := 2000 / proj.metersPerUnit
+	if dx := x1 - x0; dx < minSpan {
+		x0, x1 = x0-(minSpan-dx)/2, x1+(minSpan-dx)/2
+	}
+	if dy := y1 - y0; dy < minSpan {
+		y0, y1 = y0-(minSpan-dy)/2, y1+(minSpan-dy)/2
+	}
+	scale := math.Min((width-2*pad)/(x1-x0), (height-2*pad)/(y1-y0))
+	cx, cy := mapX+width/2, mapY+height/2
+	mx, my := (x0+x1)/2, (y0+y1)/2
+	at := func(lat, lng float64) (float64, float64) {
+		x, y := proj.xy(lat, lng)
+		return cx + (x-mx)*scale, cy - (y-my)*scale
+	}
+
+	savedLineW := doc.pdf.GetLineWidth()
+	doc.pdf.ClipRect(mapX, mapY, width, height, false)
+
+	// ── State boundaries ─────────────────────────────────────────────────────
+	if m.StateBoundaries {
+		color := m.BoundaryColor
+		if color == (Color{}) {
+			color = doc.theme.AccentColor
+		}
+		// Visible lat/lng window, with a margin for the projection's curvature.
+		vLatMin, vLngMin := proj.latLng(mx-(width/2)/scale, my-(height/2)/scale)
+		vLatMax, vLngMax := proj.latLng(mx+(width/2)/scale, my+(height/2)/scale)
+		doc.applyColor(color)
+		doc.pdf.SetLineWidth(0.25)
+		for _, l := range usStateLines() {
+			if l.latMax < vLatMin-1 || l.latMin > vLatMax+1 || l.lngMax < vLngMin-1 || l.lngMin > vLngMax+1 {
+				continue
+			}
+			for i, p := range l.points {
+				x, y := at(p.Lat, p.Lng)
+				if i == 0 {
+					doc.pdf.MoveTo(x, y)
+				} else {
+					doc.pdf.LineTo(x, y)
+				}
+			}
+			doc.pdf.DrawPath("D")
+		}
+	}
+
+	// ── Route ────────────────────────────────────────────────────────────────
+	doc.pdf.SetLineWidth(lineW)
+	doc.pdf.SetLineCapStyle("round")
+	doc.pdf.SetLineJoinStyle("round")
+	first, last := -1, -1
+	for _, run := range geoRuns(m.Points) {
+		if first < 0 {
+			first = run[0]
+		}
+		last = run[len(run)-1]
+		if len(run) == 1 {
+			p := m.Points[run[0]]
+			x, y := at(p.Lat, p.Lng)
+			doc.applyColor(m.segmentColor(bands, p.Speed))
+			doc.pdf.Circle(x, y, lineW/2, "F")
+			continue
+		}
+		// One path per run of segments in the same color; points closer than
+		// 0.2 mm to the last drawn one are skipped to keep 1 s pings compact.
+		var color Color
+		var lx, ly float64
+		open := false
+		for k := 1; k < len(run); k++ {
+			a, b := m.Points[run[k-1]], m.Points[run[k]]
+			c := m.segmentColor(bands, (a.Speed+b.Speed)/2)
+			if !open || c != color {
+				if open {
+					doc.pdf.DrawPath("D")
+				}
+				color, open = c, true
+				doc.applyColor(color)
+				lx, ly = at(a.Lat, a.Lng)
+				doc.pdf.MoveTo(lx, ly)
+			}
+			x, y := at(b.Lat, b.Lng)
+			if k < len(run)-1 && math.Hypot(x-lx, y-ly) < 0.2 {
+				continue
+			}
+			doc.pdf.LineTo(x, y)
+			lx, ly = x, y
+		}
+		doc.pdf.DrawPath("D")
+	}
+	doc.pdf.SetLineCapStyle("butt")
+	doc.pdf.SetLineJoinStyle("miter")
+
+	// ── Markers ──────────────────────────────────────────────────────────────
+	doc.pdf.SetLineWidth(0.4)
+	for _, mk := range []struct {
+		i     int
+		color Color
+	}{{first, routeStartColor}, {last, routeEndColor}} {
+		if mk.i < 0 {
+			continue
+		}
+		x, y := at(m.Points[mk.i].Lat, m.Points[mk.i].Lng)
+		doc.pdf.SetDrawColor(255, 255, 255)
+		doc.pdf.SetFillColor(mk.color.R, mk.color.G, mk.color.B)
+		doc.pdf.Circle(x, y, 1.6, "FD")
+	}
+	stopColor := m.StopColor
+	if stopColor == (Color{}) {
+		stopColor = doc.theme.SectionLabelLeft
+	}
+	pinFont := FontConfig{Family: font.Family, Size: 6, Style: "B"}
+	for i, s := range m.Stops {
+		if !validLatLng(s.Lat, s.Lng) {
+			continue
+		}
+		label := s.Label
+		if label == "" {
+			label = strconv.Itoa(i + 1)
+		}
+		x, y := at(s.Lat, s.Lng)
+		drawRoutePin(doc, x, y, label, stopColor, pinFont)
+	}
+
+	doc.pdf.ClipEnd()
+
+	// ── Scale bar and frame ──────────────────────────────────────────────────
+	if m.ShowScale {
+		drawScaleBar(doc, mapX+3, mapY+height-3, width/4, proj.metersPerUnit/scale, m.ScaleUnit, font)
+	}
+	doc.applyColor(doc.theme.TableBorderColor)
+	doc.pdf.SetLineWidth(0.2)
+	doc.pdf.Rect(mapX, mapY, width, height, "D")
+	doc.pdf.SetLineWidth(savedLineW)
+
+	doc.setY(mapY + height + 2)
+	return nil
+}
+
+// segmentColor returns the color of a route segment at speed v.
+func (m *RouteMapComponent) segmentColor(bands []SpeedBand, v float64) Color {
+	if m.Color != (Color{}) {
+		return m.Color
+	}
+	if math.IsNaN(v) {
+		return bands[0].Color
+	}
+	for _, b := range bands {
+		if v <= b.Max {
+			return b.Color
+		}
+	}
+	return bands[len(bands)-1].Color
+}
+
+// speedBandLabel returns the legend label of band i, e.g. "35–70 mph" or
+// "> 70 mph" for the last.
+func speedBandLabel(bands []SpeedBand, i int, unit string) string {
+	if bands[i].Label != "" {
+		return bands[i].Label
+	}
+	lo := 0.0
+	if i > 0 {
+		lo = bands[i-1].Max
+	}
+	num := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	if i == len(bands)-1 && i > 0 {
+		return "> " + num(lo) + " " + unit
+	}
+	return num(lo) + "–" + num(bands[i].Max) + " " + unit
+}
+
+// validLatLng reports whether lat/lng is a usable fix. Latitudes beyond ±85°
+// cannot be shown in Web Mercator and never occur on a road trip.
+func validLatLng(lat, lng float64) bool {
+	return !math.IsNaN(lat) && !math.IsNaN(lng) && math.Abs(lat) <= 85 && math.Abs(lng) <= 180
+}
+
+// geoRuns splits points into continuous runs of indexes, breaking at invalid
+// fixes.
+func geoRuns(pts []GeoPoint) [][]int {
+	var runs [][]int
+	var run []int
+	for i, p := range pts {
+		if !validLatLng(p.Lat, p.Lng) {
+			if len(run) > 0 {
+				runs = append(runs, run)
+				run = nil
+			}
+			continue
+		}
+		run = append(run, i)
+	}
+	if len(run) > 0 {
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// earthRadius is the WGS 84 equatorial radius in meters, as used by Web
+// Mercator.
+const earthRadius = 6378137.0
+
+// geoProjection projects lat/lng into planar units, x east and y north.
+type geoProjection struct {
+	kind          MapProjection
+	cosLat        float64 // cosine of the center latitude
+	metersPerUnit float64 // ground distance of one unit at the center latitude
+}
+
+func newGeoProjection(kind MapProjection, centerLat float64) geoProjection {
+	cos := math.Cos(centerLat * math.Pi / 180)
+	p := geoProjection{kind: kind, cosLat: cos, metersPerUnit: earthRadius}
+	if kind == ProjectionMercator {
+		p.metersPerUnit = earthRadius * cos
+	}
+	return p
+}
+
+// xy returns the projected coordinates of lat/lng.
+func (p geoProjection) xy(lat, lng float64) (x, y float64) {
+	phi, lambda := lat*math.Pi/180, lng*math.Pi/180
+	if p.kind == ProjectionEquirectangular {
+		return lambda * p.cosLat, phi
+	}
+	return lambda, math.Log(math.Tan(math.Pi/4 + phi/2))
+}
+
+// latLng is the inverse of xy.
+func (p geoProjection) latLng(x, y float64) (lat, lng float64) {
+	if p.kind == ProjectionEquirectangular {
+		return y * 180 / math.Pi, x / p.cosLat * 180 / math.Pi
+	}
+	return (2*math.Atan(math.Exp(y)) - math.Pi/2) * 180 / math.Pi, x * 180 / math.Pi
+}
+
+// drawRoutePin draws a teardrop pin pointing at (x, y) with label in its head.
+func drawRoutePin(doc *Document, x, y float64, label string, color Color, font FontConfig) {
+	doc.applyFont(font)
+	r := math.Max(2, doc.stringWidth(label)/2+0.5)
+	hy := y - r - 1.6 // head center
+	doc.applyColor(color)
+	doc.pdf.Polygon([]fpdf.PointType{{X: x, Y: y}, {X: x - r*0.8, Y: hy + r*0.6}, {X: x + r*0.8, Y: hy + r*0.6}}, "F")
+	doc.pdf.Circle(x, hy, r, "F")
+	doc.applyTextColor(contrastText(doc, color))
+	cm := doc.pdf.GetCellMargin()
+	doc.pdf.SetCellMargin(0)
+	doc.pdf.SetXY(x-r, hy-r)
+	doc.cellFormat(2*r, 2*r, label, "", 0, "C", false)
+	doc.pdf.SetCellMargin(cm)
+}
+
+// drawScaleBar draws a scale bar whose bottom-left corner is (x, y), at most
+// maxW mm long, for a map of metersPerMM at its center.
+func drawScaleBar(doc *Document, x, y, maxW, metersPerMM float64, unit DistanceUnit, font FontConfig) {
+	unitM, suffix := unit.meters(), unit.suffix()
+	maxDist := maxW * metersPerMM / unitM
+	if unit == Meters && maxDist >= 1000 {
+		unitM, suffix, maxDist = 1000, Kilometers.suffix(), maxDist/1000
+	}
+	// Largest 1, 2 or 5 × 10^n that fits.
+	mag := math.Pow(10, math.Floor(math.Log10(maxDist)))
+	dist := mag
+	for _, f := range []float64{5, 2} {
+		if f*mag <= maxDist {
+			dist = f * mag
+			break
+		}
+	}
+	w := dist * unitM / metersPerMM
+	text := formatNumber(dist, tickDecimals(dist)) + suffix
+
+	doc.applyFont(font)
+	labelW := doc.stringWidth(text) + 2*doc.pdf.GetCellMargin()
+	const h = 3.5
+	doc.pdf.SetAlpha(0.8, "Normal")
+	doc.pdf.SetFillColor(255, 255, 255)
+	doc.pdf.Rect(x-1, y-h-1.5, w+labelW+2, h+2.5, "F")
+	doc.pdf.SetAlpha(1, "Normal")
+
+	doc.applyColor(doc.theme.PrimaryText)
+	doc.pdf.SetLineWidth(0.3)
+	doc.pdf.Line(x, y-1, x+w, y-1)
+	doc.pdf.Line(x, y-2.2, x, y-1)
+	doc.pdf.Line(x+w, y-2.2, x+w, y-1)
+	doc.applyTextColor(doc.theme.PrimaryText)
+	doc.pdf.SetXY(x+w, y-h)
+	doc.cellFormat(labelW, h, text, "", 0, "L", false)
+}
+
+// geoLine is one polyline of the bundled boundary data with its bounds.
+type geoLine struct {
+	points                         []GeoPoint
+	latMin, latMax, lngMin, lngMax float64
+}
+
+//go:embed mapdata/us_states.txt
+var usStatesData string
+
+// usStateLines parses the bundled state boundaries once.
+var usStateLines = sync.OnceValue(func() []geoLine {
+	var lines []geoLine
+	for _, row := range strings.Split(usStatesData, "\n") {
+		fields := strings.Fields(row)
+		if len(fields) < 3 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		l := geoLine{latMin: 90, latMax: -90, lngMin: 180, lngMax: -180}
+		for _, f := range fields[1:] {
+			latS, lngS, _ := strings.Cut(f, ",")
+			lat, err1 := strconv.ParseFloat(latS, 64)
+			lng, err2 := strconv.ParseFloat(lngS, 64)
+			if err1 != nil || err2 != nil {
+				panic("pdfgen: bad vertex " + f + " in mapdata/us_states.txt")
+			}
+			l.points = append(l.points, GeoPoint{Lat: lat, Lng: lng})
+			l.latMin, l.latMax = math.Min(l.latMin, lat), math.Max(l.latMax, lat)
+			l.lngMin, l.lngMax = math.Min(l.lngMin, lng), math.Max(l.lngMax, lng)
+		}
+		lines = append(lines, l)
+	}
+	return lines
+})
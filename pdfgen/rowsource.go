@@ -0,0 +1,178 @@
+package pdfgen
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RowSource streams table rows one at a time, so a TableComponent can render
+// millions of rows without holding them in memory. Each row is a slice of
+// values accepted in TableComponent.Data.
+//
+// pgx.Rows is a RowSource, so the result of postgresql.Client.Query can be
+// passed straight to TableComponent.Source:
+//
+//	rows, err := db.Query(ctx, `SELECT recorded_at, lat, lng, speed FROM pings WHERE ...`)
+//	if err != nil { ... }
+//	defer rows.Close()
+//	doc.Add(&pdfgen.TableComponent{Columns: cols, Source: rows, ShowHeader: true})
+//
+// Sources with a Close() method are closed when Render returns.
+type RowSource interface {
+	Next() bool             // advances to the next row; false at the end or on error
+	Values() ([]any, error) // values of the current row
+	Err() error             // error that stopped iteration, if any
+}
+
+// pgx.Rows from postgresql.Client.Query.
+var _ RowSource = pgx.Rows(nil)
+
+// SeqSource adapts an iterator of rows to a RowSource.
+func SeqSource(seq iter.Seq[[]any]) RowSource {
+	next, stop := iter.Pull(seq)
+	return &seqSource{next: next, stop: stop}
+}
+
+type seqSource struct {
+	next func() ([]any, bool)
+	stop func()
+	row  []any
+}
+
+func (s *seqSource) Next() bool {
+	row, ok := s.next()
+	s.row = row
+	return ok
+}
+
+func (s *seqSource) Values() ([]any, error) { return s.row, nil }
+func (s *seqSource) Err() error             { return nil }
+func (s *seqSource) Close()                 { s.stop() }
+
+// tableRow is one row read by a rowReader. key holds the GroupBy values
+// and is nil for an ungrouped table.
+type tableRow struct {
+	cells []Cell
+	key   []string
+}
+
+// rowReader reads the rows of a TableComponent from Source, Data or Rows,
+// in that order of precedence, converting them to cells one at a time.
+type rowReader struct {
+	t        *TableComponent
+	index    int
+	hidden   map[int]bool // GroupBy columns removed by HideGroupColumns
+	rules    []compiledRule
+	buffered []*tableRow // rows read ahead by peek
+}
+
+// next returns the next row, or nil at the end of the table.
+func (r *rowReader) next() (*tableRow, error) {
+	if len(r.buffered) > 0 {
+		row := r.buffered[0]
+		r.buffered = r.buffered[1:]
+		return row, nil
+	}
+	return r.read()
+}
+
+// peek reads up to n rows ahead without consuming them and returns their
+// cells.
+func (r *rowReader) peek(n int) ([][]Cell, error) {
+	for len(r.buffered) < n {
+		row, err := r.read()
+		if err != nil {
+			return nil, err
+		}
+		if row == nil {
+			break
+		}
+		r.buffered = append(r.buffered, row)
+	}
+	cells := make([][]Cell, len(r.buffered))
+	for i, row := range r.buffered {
+		cells[i] = row.cells
+	}
+	return cells, nil
+}
+
+// read converts the next row of the underlying source.
+func (r *rowReader) read() (*tableRow, error) {
+	t := r.t
+	var cells []Cell
+	switch {
+	case t.Source != nil:
+		if !t.Source.Next() {
+			return nil, t.Source.Err()
+		}
+		values, err := t.Source.Values()
+		if err != nil {
+			return nil, err
+		}
+		if cells, err = r.toCells(values); err != nil {
+			return nil, err
+		}
+	case t.Data != nil:
+		if r.index >= len(t.Data) {
+			return nil, nil
+		}
+		var err error
+		if cells, err = r.toCells(t.Data[r.index]); err != nil {
+			return nil, err
+		}
+	default:
+		if r.index >= len(t.Rows) {
+			return nil, nil
+		}
+		cells = textCells(t.Rows[r.index])
+	}
+	r.index++
+	if len(r.rules) > 0 {
+		cells = applyRules(r.rules, cells, len(t.Columns))
+	}
+
+	row := &tableRow{cells: cells}
+	if len(t.GroupBy) > 0 {
+		row.key = make([]string, len(t.GroupBy))
+		for i, k := range t.GroupBy {
+			if k < len(cells) {
+				row.key[i] = cells[k].Text
+			}
+		}
+	}
+	if r.hidden != nil {
+		row.cells = hideColumns(cells, r.hidden)
+	}
+	return row, nil
+}
+
+// toCells converts one row of values to cells. Covered columns of a cell
+// with Span > 1 are stored as empty cells so that row[i] always belongs to
+// column i.
+func (r *rowReader) toCells(values []any) ([]Cell, error) {
+	cells := make([]Cell, 0, len(r.t.Columns))
+	for j, v := range values {
+		var col ColumnDef
+		if len(cells) < len(r.t.Columns) {
+			col = r.t.Columns[len(cells)]
+		}
+		c, ok := toCell(col, v)
+		if !ok {
+			return nil, fmt.Errorf("pdfgen: TableComponent row %d column %d has unsupported type %T", r.index, j, v)
+		}
+		cells = append(cells, c)
+		for k := 1; k < c.Span; k++ {
+			cells = append(cells, Cell{})
+		}
+	}
+	return cells, nil
+}
+
+// close closes the Source if it has a Close method.
+func (r *rowReader) close() {
+	if c, ok := r.t.Source.(interface{ Close() }); ok {
+		c.Close()
+	}
+}
@@ -0,0 +1,223 @@
+package pdfgen
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// CompareOp is the numeric comparison of a FormatRule.
+type CompareOp int
+
+const (
+	// CompareNone skips the numeric test.
+	CompareNone CompareOp = iota
+	// CompareGT matches values greater than FormatRule.Value.
+	CompareGT
+	// CompareGE matches values greater than or equal to FormatRule.Value.
+	CompareGE
+	// CompareLT matches values less than FormatRule.Value.
+	CompareLT
+	// CompareLE matches values less than or equal to FormatRule.Value.
+	CompareLE
+	// CompareEQ matches values equal to FormatRule.Value.
+	CompareEQ
+	// CompareNE matches numeric values other than FormatRule.Value.
+	CompareNE
+)
+
+// CellStyle is the style a FormatRule applies. Zero fields leave the cell
+// unchanged.
+type CellStyle struct {
+	TextColor Color
+	Fill      Color
+	Bold      bool
+	Icon      Icon  // drawn before the text
+	IconColor Color // zero value → text color
+}
+
+// FormatRule conditionally styles table rows or cells.
+//
+// The predicate tests the text of column Column and holds when every test
+// that is set passes:
+//
+//	Op/Value — numeric comparison of the cell's typed Value, durations in
+//	           hours; for text cells "1,240 mi", "$12.50" and "11:45"
+//	           (hours:minutes, as 11.75) are numeric, other text never matches
+//	Pattern  — regular expression
+//	In       — set membership
+//	Func     — custom predicate over the whole row's text
+//
+// Rules run in order for every row. A matching rule overrides the style
+// fields it sets, so later rules win; Stop skips the rules after it. Styles
+// apply on top of Cell styles given in Data.
+type FormatRule struct {
+	Column  int                     // index in Columns of the tested cell
+	Op      CompareOp               // numeric comparison against Value
+	Value   float64                 // e.g. 11 for "duty hours above 11"
+	Pattern string                  // regular expression the cell text must match
+	In      []string                // cell text must equal one of these
+	Func    func(row []string) bool // custom predicate over the row
+	Row     bool                    // style every cell of the row, not just Column
+	Style   CellStyle
+	Stop    bool // skip the remaining rules when this one matches
+}
+
+// compiledRule is a FormatRule with its Pattern compiled.
+type compiledRule struct {
+	FormatRule
+	re *regexp.Regexp
+	in map[string]bool
+}
+
+// compileRules validates the rules of t.
+func (t *TableComponent) compileRules() ([]compiledRule, error) {
+	rules := make([]compiledRule, len(t.Rules))
+	for i, r := range t.Rules {
+		if r.Column < 0 || r.Column >= len(t.Columns) {
+			return nil, fmt.Errorf("pdfgen: TableComponent Rules[%d] column %d out of range [0, %d)", i, r.Column, len(t.Columns))
+		}
+		rules[i].FormatRule = r
+		if r.Pattern != "" {
+			re, err := regexp.Compile(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("pdfgen: TableComponent Rules[%d] pattern: %w", i, err)
+			}
+			rules[i].re = re
+		}
+		if r.In != nil {
+			rules[i].in = make(map[string]bool, len(r.In))
+			for _, s := range r.In {
+				rules[i].in[s] = true
+			}
+		}
+	}
+	return rules, nil
+}
+
+// hoursMinutes matches durations like "11:45", "-1:30" or "7:05:30".
+var hoursMinutes = regexp.MustCompile(`^\s*(-?)(\d+):([0-5]\d)(?::([0-5]\d))?\s*$`)
+
+// ruleValue returns the number a numeric rule compares for a cell: its typed
+// Value, durations in hours, or for text cells the parsed text.
+func ruleValue(c Cell) (float64, bool) {
+	switch v := c.Value.(type) {
+	case nil:
+		return ruleNumber(c.Text)
+	case string:
+		return ruleNumber(v)
+	case time.Duration:
+		return v.Hours(), true
+	}
+	return numberOf(c.Value)
+}
+
+// ruleNumber parses cell text for a numeric rule.
+func ruleNumber(s string) (float64, bool) {
+	if m := hoursMinutes.FindStringSubmatch(s); m != nil {
+		h, _ := strconv.Atoi(m[2])
+		mins, _ := strconv.Atoi(m[3])
+		secs := 0
+		if m[4] != "" {
+			secs, _ = strconv.Atoi(m[4])
+		}
+		v := float64(h) + float64(mins)/60 + float64(secs)/3600
+		if m[1] != "" {
+			v = -v
+		}
+		return v, true
+	}
+	v, _, _, _, ok := parseNumericCell(s)
+	return v, ok
+}
+
+// matches reports whether the rule holds for a row.
+func (r *compiledRule) matches(row []Cell) bool {
+	var cell Cell
+	if r.Column < len(row) {
+		cell = row[r.Column]
+	}
+	text := cell.Text
+	if r.Op != CompareNone {
+		v, ok := ruleValue(cell)
+		if !ok {
+			return false
+		}
+		switch r.Op {
+		case CompareGT:
+			ok = v > r.Value
+		case CompareGE:
+			ok = v >= r.Value
+		case CompareLT:
+			ok = v < r.Value
+		case CompareLE:
+			ok = v <= r.Value
+		case CompareEQ:
+			ok = v == r.Value
+		case CompareNE:
+			ok = v != r.Value
+		}
+		if !ok {
+			return false
+		}
+	}
+	if r.re != nil && !r.re.MatchString(text) {
+		return false
+	}
+	if r.in != nil && !r.in[text] {
+		return false
+	}
+	if r.Func != nil {
+		texts := make([]string, len(row))
+		for i, c := range row {
+			texts[i] = c.Text
+		}
+		if !r.Func(texts) {
+			return false
+		}
+	}
+	return true
+}
+
+// applyRules styles the cells of a row, padded to one cell per column, with
+// the matching rules.
+func applyRules(rules []compiledRule, row []Cell, n int) []Cell {
+	for len(row) < n {
+		row = append(row, Cell{})
+	}
+	for i := range rules {
+		r := &rules[i]
+		if !r.matches(row) {
+			continue
+		}
+		if r.Row {
+			for c := range row {
+				r.Style.apply(&row[c])
+			}
+		} else {
+			r.Style.apply(&row[r.Column])
+		}
+		if r.Stop {
+			break
+		}
+	}
+	return row
+}
+
+// apply overrides the cell fields the style sets.
+func (s CellStyle) apply(c *Cell) {
+	if s.TextColor != (Color{}) {
+		c.TextColor = s.TextColor
+	}
+	if s.Fill != (Color{}) {
+		c.Fill = s.Fill
+	}
+	if s.Bold {
+		c.Font.Style = "B"
+	}
+	if s.Icon != IconNone {
+		c.Icon = s.Icon
+		c.IconColor = s.IconColor
+	}
+}
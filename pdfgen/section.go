@@ -0,0 +1,174 @@
+package pdfgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// SectionConfig configures the pages of a document section, e.g. a
+// landscape movement table after a portrait IFTA summary. Zero fields keep
+// the value of the previous section.
+type SectionConfig struct {
+	PageSize     string               // "A4", "Letter", …
+	Orientation  string               // "portrait" or "landscape"
+	MarginTop    float64              // mm
+	MarginBottom float64              // mm
+	MarginLeft   float64              // mm
+	MarginRight  float64              // mm
+	Footer       *FooterComponent     // footer of the section's pages
+	PageHeader   *PageHeaderComponent // page header of the section's pages
+	NoFooter     bool                 // no footer in this section
+	NoPageHeader bool                 // no page header in this section
+
+	// RestartNumbering numbers the section's pages from 1: {page} and
+	// {total} count pages from the start of this section up to the next
+	// section that restarts numbering. Default: numbering continues.
+	RestartNumbering bool
+}
+
+// section is the resolved page geometry of a run of pages.
+type section struct {
+	orientation string // "P" or "L"
+	size        fpdf.SizeType
+	w, h        float64 // page size in mm, oriented
+	marginT     float64
+	marginB     float64
+	marginL     float64
+	marginR     float64
+	restart     bool
+}
+
+// newSection returns the section geometry of a page size and orientation.
+func newSection(size fpdf.SizeType, landscape bool, restart bool) *section {
+	s := &section{orientation: "P", size: size, w: size.Wd, h: size.Ht, restart: restart}
+	if landscape {
+		s.orientation = "L"
+		s.w, s.h = size.Ht, size.Wd
+	}
+	return s
+}
+
+// NewSection ends the current page and starts a section on a new page with
+// its own page size, orientation, margins, footer and page header. Before
+// any content has been added, the section starts on the first page instead.
+func (d *Document) NewSection(cfg SectionConfig) *Document {
+	if d.err != nil {
+		return d
+	}
+	prev := d.sec
+	size := prev.size
+	if cfg.PageSize != "" {
+		size = d.pdf.GetPageSizeStr(cfg.PageSize)
+		if d.pdf.Error() != nil {
+			d.err = fmt.Errorf("pdfgen: NewSection: unknown page size %q", cfg.PageSize)
+			return d
+		}
+	}
+	landscape := prev.orientation == "L"
+	if cfg.Orientation != "" {
+		landscape = strings.EqualFold(cfg.Orientation, "landscape")
+	}
+	sec := newSection(size, landscape, cfg.RestartNumbering)
+	sec.marginT = pick(cfg.MarginTop, prev.marginT)
+	sec.marginB = pick(cfg.MarginBottom, prev.marginB)
+	sec.marginL = pick(cfg.MarginLeft, prev.marginL)
+	sec.marginR = pick(cfg.MarginRight, prev.marginR)
+
+	if cfg.PageHeader != nil {
+		d.header = cfg.PageHeader
+	} else if cfg.NoPageHeader {
+		d.header = nil
+	}
+	d.useSection(sec)
+	if d.pdf.PageNo() > 0 {
+		d.addPage()
+	}
+	// The footer callback has recorded the previous page's footer.
+	if cfg.Footer != nil {
+		d.footer = cfg.Footer
+	} else if cfg.NoFooter {
+		d.footer = nil
+	}
+	return d
+}
+
+func pick(v, prev float64) float64 {
+	if v != 0 {
+		return v
+	}
+	return prev
+}
+
+// useSection makes sec the geometry of the current page.
+func (d *Document) useSection(sec *section) {
+	d.sec = sec
+	d.marginT, d.marginB, d.marginL, d.marginR = sec.marginT, sec.marginB, sec.marginL, sec.marginR
+	d.pageWidth = sec.w - sec.marginL - sec.marginR
+	d.pdf.SetMargins(sec.marginL, sec.marginT, sec.marginR)
+}
+
+// addPage starts a new page in the current section's format.
+func (d *Document) addPage() {
+	d.pdf.AddPageFormat(d.sec.orientation, d.sec.size)
+}
+
+// ensurePage adds the first page unless there is one. New leaves it to the
+// first content so that a NewSection before it sets the page's format.
+func (d *Document) ensurePage() {
+	if d.pdf.PageNo() == 0 {
+		d.addPage()
+	}
+}
+
+// setPage switches to page n to draw on it while finishing the document.
+// fpdf places output by the height of the last page added, so a page of
+// another height is drawn through a vertical shift of pageShift mm.
+func (d *Document) setPage(n int) {
+	d.endPage()
+	d.pdf.SetPage(n)
+	sec := d.page(n).sec
+	d.useSection(sec)
+	d.pageShift = d.page(d.pdf.PageCount()).sec.h - sec.h
+	if d.pageShift != 0 {
+		d.pdf.TransformBegin()
+		d.pdf.TransformTranslate(0, d.pageShift)
+	}
+}
+
+// endPage ends the shift started by setPage.
+func (d *Document) endPage() {
+	if d.pageShift != 0 {
+		d.pdf.TransformEnd()
+		d.pageShift = 0
+	}
+}
+
+// numberPages returns the printed number of every page and the page count
+// it is shown against, indexed by page-1, counting pages in output order
+// and following RestartNumbering.
+func (d *Document) numberPages() (numbers, totals []int) {
+	total := d.pdf.PageCount()
+	d.page(total)
+	order := d.pageOrder()
+	if order == nil {
+		order = make([]int, total)
+		for i := range order {
+			order[i] = i + 1
+		}
+	}
+	numbers = make([]int, total)
+	totals = make([]int, total)
+	start := 0
+	for i := 0; i <= total; i++ {
+		if i == total || (i > 0 && d.page(order[i]).sec != d.page(order[i-1]).sec && d.page(order[i]).sec.restart) {
+			for j := start; j < i; j++ {
+				numbers[order[j]-1] = j - start + 1
+				totals[order[j]-1] = i - start
+			}
+			start = i
+		}
+	}
+	return numbers, totals
+}
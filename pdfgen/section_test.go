@@ -0,0 +1,61 @@
+package pdfgen
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestSectionNumbering(t *testing.T) {
+	doc := New(DocumentConfig{})
+	doc.pdf.SetCompression(false)
+	doc.SetFooter(&FooterComponent{
+		CenterText: "Page {page} of {total}",
+		FirstPage:  &FooterComponent{CenterText: "Cover {page} of {total}"},
+		EvenPages:  &FooterComponent{CenterText: "Even {page} of {total}"},
+	})
+	body := &SpacerComponent{Height: 10}
+	doc.Add(body)
+	doc.NewSection(SectionConfig{}).Add(body)
+	doc.NewSection(SectionConfig{}).Add(body)
+	doc.NewSection(SectionConfig{Orientation: "landscape", RestartNumbering: true}).Add(body)
+	doc.NewSection(SectionConfig{}).Add(body)
+
+	b, err := doc.Bytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pages := []struct {
+		orientation   string
+		number, total int
+		footer        string
+	}{
+		{"P", 1, 3, "Cover 1 of 3"},
+		{"P", 2, 3, "Even 2 of 3"},
+		{"P", 3, 3, "Page 3 of 3"},
+		{"L", 1, 2, "Cover 1 of 2"},
+		{"L", 2, 2, "Even 2 of 2"},
+	}
+	if n := doc.pdf.PageCount(); n != len(pages) {
+		t.Fatalf("%d pages, want %d", n, len(pages))
+	}
+	numbers, totals := doc.numberPages()
+	// Content streams are written in page order.
+	var footers []string
+	for _, m := range regexp.MustCompile(`\(((?:Cover|Even|Page) [^)]*)\)\s*Tj`).FindAllSubmatch(b, -1) {
+		footers = append(footers, string(m[1]))
+	}
+	if len(footers) != len(pages) {
+		t.Fatalf("footers %q, want one per page", footers)
+	}
+	for i, want := range pages {
+		if o := doc.page(i + 1).sec.orientation; o != want.orientation {
+			t.Errorf("page %d orientation %s, want %s", i+1, o, want.orientation)
+		}
+		if numbers[i] != want.number || totals[i] != want.total {
+			t.Errorf("page %d numbered %d of %d, want %d of %d", i+1, numbers[i], totals[i], want.number, want.total)
+		}
+		if footers[i] != want.footer {
+			t.Errorf("page %d footer %q, want %q", i+1, footers[i], want.footer)
+		}
+	}
+}
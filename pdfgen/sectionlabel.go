@@ -21,6 +21,8 @@ type SectionLabelComponent struct {
 	RightLabelColor Color      // color for the label part before ":" in RightText; zero = SecondaryText
 	RightColor      Color      // color for the value part after ":" in RightText; zero = AccentColor
 	MarginBottom    float64    // mm below the line; default 2
+	Bookmark        string     // outline title; default LeftText
+	NoBookmark      bool       // leave the label out of the outline
 }
 
 // Render draws the section label row and advances the Y cursor.
@@ -41,6 +43,15 @@ func (s *SectionLabelComponent) Render(doc *Document) error {
 	const lineH = 7.0
 	startY := doc.currentY()
 
+	// Bookmark one level below the last HeadingComponent.
+	if !s.NoBookmark {
+		title := s.Bookmark
+		if title == "" {
+			title = s.LeftText
+		}
+		doc.bookmark(title, doc.sectionDepth)
+	}
+
 	// ── Left text ────────────────────────────────────────────────────────────
 	// When LeftText contains ":", split into label (secondary) + value (accent).
 	if s.LeftText != "" {
@@ -50,30 +61,30 @@ func (s *SectionLabelComponent) Render(doc *Document) error {
 			valuePart := leftParts[1]
 
 			doc.applyFont(leftFont)
-			labelW := doc.pdf.GetStringWidth(labelPart) + 1
+			labelW := doc.stringWidth(labelPart) + 1
 
 			accentFont := leftFont
 			accentFont.Style = "B"
 			doc.applyFont(accentFont)
-			valueW := doc.pdf.GetStringWidth(valuePart) + 1
+			valueW := doc.stringWidth(valuePart) + 1
 
 			// Label part → secondary color
 			doc.applyFont(leftFont)
 			doc.applyTextColor(doc.theme.SecondaryText)
 			doc.pdf.SetXY(doc.marginL, startY)
-			doc.pdf.CellFormat(labelW, lineH, labelPart, "", 0, "L", false, 0, "")
+			doc.cellFormat(labelW, lineH, labelPart, "", 0, "L", false)
 
 			// Value part → accent color
 			doc.applyFont(accentFont)
 			doc.applyTextColor(doc.theme.AccentColor)
 			doc.pdf.SetXY(doc.marginL+labelW, startY)
-			doc.pdf.CellFormat(valueW+2, lineH, valuePart, "", 0, "L", false, 0, "")
+			doc.cellFormat(valueW+2, lineH, valuePart, "", 0, "L", false)
 		} else {
 			// No colon → plain bold primary text
 			doc.applyFont(leftFont)
 			doc.applyTextColor(doc.theme.PrimaryText)
 			doc.pdf.SetXY(doc.marginL, startY)
-			doc.pdf.CellFormat(doc.usableWidth()*0.5, lineH, s.LeftText, "", 0, "L", false, 0, "")
+			doc.cellFormat(doc.usableWidth()*0.5, lineH, s.LeftText, "", 0, "L", false)
 		}
 	}
 
@@ -96,10 +107,10 @@ func (s *SectionLabelComponent) Render(doc *Document) error {
 			valuePart := parts[1]
 
 			doc.applyFont(rightFont)
-			labelW := doc.pdf.GetStringWidth(labelPart) + 1
+			labelW := doc.stringWidth(labelPart) + 1
 
 			doc.applyFont(rightFont)
-			valueW := doc.pdf.GetStringWidth(valuePart) + 2 // +2 for gap
+			valueW := doc.stringWidth(valuePart) + 2 // +2 for gap
 
 			startRX := rightEdge - labelW - valueW
 
@@ -107,19 +118,19 @@ func (s *SectionLabelComponent) Render(doc *Document) error {
 			doc.applyFont(rightFont)
 			doc.applyTextColor(rightLabelColor)
 			doc.pdf.SetXY(startRX, startY)
-			doc.pdf.CellFormat(labelW, lineH, labelPart, "", 0, "L", false, 0, "")
+			doc.cellFormat(labelW, lineH, labelPart, "", 0, "L", false)
 
 			// Value part
 			doc.applyFont(rightFont)
 			doc.applyTextColor(rightValueColor)
 			doc.pdf.SetXY(startRX+labelW, startY)
-			doc.pdf.CellFormat(valueW+1, lineH, valuePart, "", 0, "L", false, 0, "")
+			doc.cellFormat(valueW+1, lineH, valuePart, "", 0, "L", false)
 		} else {
 			// No colon → right-aligned, right label color
 			doc.applyFont(rightFont)
 			doc.applyTextColor(rightLabelColor)
 			doc.pdf.SetXY(doc.marginL, startY)
-			doc.pdf.CellFormat(doc.usableWidth(), lineH, s.RightText, "", 0, "R", false, 0, "")
+			doc.cellFormat(doc.usableWidth(), lineH, s.RightText, "", 0, "R", false)
 		}
 	}
 
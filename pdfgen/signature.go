@@ -0,0 +1,131 @@
+package pdfgen
+
+import (
+	"fmt"
+	"time"
+)
+
+// DefaultCertification is the driver's certification of a record of duty
+// status under 49 CFR 395.8.
+const DefaultCertification = "I hereby certify that my data entries and my record of duty status for this 24-hour period are true and correct."
+
+// SignatureBlockComponent renders a certification statement above a
+// signature line with the signer's name, role and signing time. The
+// signature image, e.g. a PNG captured on the driver's tablet, sits on the
+// line; without one the line is left empty to sign on paper. The block is
+// kept together on one page.
+type SignatureBlockComponent struct {
+	Text          string         // certification statement, ParagraphComponent markup; default DefaultCertification
+	SignatureData []byte         // PNG/JPG of the signature; nil → empty line
+	SignerName    string         // e.g. "John Smith"
+	Role          string         // e.g. "Driver"
+	SignedAt      time.Time      // zero → no timestamp
+	Location      *time.Location // zone SignedAt is shown in; nil → SignedAt's own
+	TimeFormat    string         // Go layout; default "01/02/2006 15:04 MST"
+	Width         float64        // mm, signature line; default 80
+	Height        float64        // mm, signature area above the line; default 18
+	Font          FontConfig     // zero value → theme DefaultFont
+	MarginBottom  float64        // mm below the block; default 4
+}
+
+// Render draws the block, first moving to a new page when it does not fit.
+func (s *SignatureBlockComponent) Render(doc *Document) error {
+	font := s.Font
+	if font.Family == "" {
+		font.Family = doc.theme.DefaultFont.Family
+	}
+	if font.Size == 0 {
+		font.Size = doc.theme.DefaultFont.Size
+	}
+	w := s.Width
+	if w == 0 {
+		w = 80
+	}
+	w = min(w, doc.usableWidth())
+	h := s.Height
+	if h == 0 {
+		h = 18
+	}
+	mb := s.MarginBottom
+	if mb == 0 {
+		mb = 4
+	}
+	text := s.Text
+	if text == "" {
+		text = DefaultCertification
+	}
+	layout := "01/02/2006 15:04 MST"
+	if s.TimeFormat != "" {
+		layout = s.TimeFormat
+	}
+	signed := ""
+	if !s.SignedAt.IsZero() {
+		t := s.SignedAt
+		if s.Location != nil {
+			t = t.In(s.Location)
+		}
+		signed = t.Format(layout)
+	}
+
+	para := &ParagraphComponent{Text: text, Font: font, MarginBottom: 2}
+	small := FontConfig{Family: font.Family, Size: font.Size - 1}
+	lineH := font.Size * 0.5
+	smallH := small.Size * 0.5
+	height := para.height(doc) + h + 1 + lineH
+	if s.Role != "" {
+		height += smallH
+	}
+	doc.newPageIfNeeded(height + mb)
+
+	if err := para.Render(doc); err != nil {
+		return err
+	}
+	x, top := doc.marginL, doc.currentY()
+	if len(s.SignatureData) > 0 {
+		name, opts := doc.image("", s.SignatureData)
+		info := doc.pdf.GetImageInfo(name)
+		if doc.pdf.Err() || info == nil || info.Height() == 0 {
+			return fmt.Errorf("pdfgen: SignatureBlockComponent: invalid signature image")
+		}
+		// Fit the image in the area, standing on the line.
+		iw, ih := w, w*info.Height()/info.Width()
+		if ih > h {
+			iw, ih = h*info.Width()/info.Height(), h
+		}
+		doc.pdf.ImageOptions(name, x, top+h-ih, iw, ih, false, opts, 0, "")
+	}
+	savedLineW := doc.pdf.GetLineWidth()
+	doc.applyColor(doc.theme.SecondaryText)
+	doc.pdf.SetLineWidth(0.3)
+	doc.pdf.Line(x, top+h, x+w, top+h)
+	doc.pdf.SetLineWidth(savedLineW)
+
+	// Name under the line, or a "Signature" caption for a blank block.
+	y := top + h + 1
+	doc.pdf.SetXY(x, y)
+	if s.SignerName != "" {
+		doc.applyFont(FontConfig{Family: font.Family, Size: font.Size, Style: "B"})
+		doc.applyTextColor(doc.theme.PrimaryText)
+		doc.cellFormat(w, lineH, s.SignerName, "", 0, "L", false)
+	} else {
+		doc.applyFont(small)
+		doc.applyTextColor(doc.theme.SecondaryText)
+		doc.cellFormat(w, lineH, "Signature", "", 0, "L", false)
+	}
+	if signed != "" {
+		doc.applyFont(small)
+		doc.applyTextColor(doc.theme.SecondaryText)
+		doc.pdf.SetXY(x, y)
+		doc.cellFormat(w, lineH, signed, "", 0, "R", false)
+	}
+	y += lineH
+	if s.Role != "" {
+		doc.applyFont(small)
+		doc.applyTextColor(doc.theme.SecondaryText)
+		doc.pdf.SetXY(x, y)
+		doc.cellFormat(w, smallH, s.Role, "", 0, "L", false)
+		y += smallH
+	}
+	doc.setY(y + mb)
+	return nil
+}
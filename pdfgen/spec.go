@@ -0,0 +1,354 @@
+package pdfgen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec is a declarative document layout: page config, theme overrides and
+// an ordered list of component blocks, with data bound from a separate JSON
+// payload. Layouts can then change without a Go release.
+//
+//	page:
+//	  orientation: landscape
+//	theme:
+//	  accent_color: "#2563EB"
+//	components:
+//	  - header: {title: IFTA REPORT, lines: ["${carrier.name}", "Q${quarter} ${year}"]}
+//	  - table:
+//	      show_header: true
+//	      rows: ${jurisdictions}
+//	      columns:
+//	        - {header: State, field: state}
+//	        - {header: Miles, field: miles, format: {kind: number}, aggregate: sum}
+//
+// Blocks are keyed by their component: header, logo, info_block, paragraph,
+// signature_block, qr_code, barcode, route_map, address_block, invoice_lines,
+// invoice_totals, table, grouped_table, spacer and footer. Their fields are the component's Go
+// fields in snake_case. A string that is exactly ${path} takes the payload
+// value at path, e.g. ${trips} or ${drivers[0].name}; inside longer strings
+// the value is inserted as text.
+type Spec struct {
+	tree any
+}
+
+// documentSpec is the top level of a spec.
+type documentSpec struct {
+	Page       DocumentConfig
+	Theme      ThemeConfig
+	Components []componentSpec
+}
+
+// componentSpec is one block of components; exactly one field is set.
+type componentSpec struct {
+	Header         *HeaderComponent
+	Logo           *LogoComponent
+	InfoBlock      *InfoBlockComponent
+	Paragraph      *ParagraphComponent
+	SignatureBlock *SignatureBlockComponent
+	QRCode         *QRCodeComponent
+	Barcode        *BarcodeComponent
+	RouteMap       *RouteMapComponent
+	AddressBlock   *AddressBlockComponent
+	InvoiceLines   *InvoiceLinesComponent
+	InvoiceTotals  *InvoiceTotalsComponent
+	Table          *tableSpec
+	GroupedTable   *groupedTableSpec
+	Spacer         *SpacerComponent
+	Footer         *FooterComponent
+}
+
+// tableSpec is a TableComponent whose rows come from Rows, a list of rows
+// given by position or as objects read by the columns' Field paths.
+type tableSpec struct {
+	TableComponent
+	Columns []columnSpec
+	Rows    any
+}
+
+type columnSpec struct {
+	ColumnDef
+	Field string // path of the cell value in an object row, e.g. "odometer.start"
+}
+
+type groupedTableSpec struct {
+	GroupedTableComponent
+	Table tableSpec
+}
+
+// ParseSpec parses a JSON or YAML spec and checks it for unknown fields and
+// type mismatches. Errors name the offending value, e.g.
+// "pdfgen: spec: components[3].table.columns[1].width: want a number, got string "wide"".
+func ParseSpec(src []byte) (*Spec, error) {
+	var tree any
+	if src = bytes.TrimSpace(src); len(src) > 0 && src[0] == '{' {
+		if err := json.Unmarshal(src, &tree); err != nil {
+			return nil, fmt.Errorf("pdfgen: spec: invalid JSON: %w", err)
+		}
+	} else if err := yaml.Unmarshal(src, &tree); err != nil {
+		return nil, fmt.Errorf("pdfgen: spec: invalid YAML: %w", err)
+	}
+	tree, err := normalizeSpec("", tree)
+	if err != nil {
+		return nil, err
+	}
+	s := &Spec{tree: tree}
+	if _, err := s.decode(&specDecoder{}, DocumentConfig{}); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Build renders the spec with the JSON payload its ${path} bindings read
+// from. base supplies what a spec cannot hold, such as Fonts and Date; the
+// spec's page and theme fields override it.
+func (s *Spec) Build(base DocumentConfig, payload []byte) (*Document, error) {
+	var data any
+	if len(bytes.TrimSpace(payload)) > 0 {
+		dec := json.NewDecoder(bytes.NewReader(payload))
+		dec.UseNumber()
+		if err := dec.Decode(&data); err != nil {
+			return nil, fmt.Errorf("pdfgen: spec: invalid payload: %w", err)
+		}
+	}
+	ds, err := s.decode(&specDecoder{data: data, bind: true}, base)
+	if err != nil {
+		return nil, err
+	}
+	cfg := ds.Page
+	cfg.Theme = ds.Theme
+	doc := New(cfg)
+	for i, c := range ds.Components {
+		path := fmt.Sprintf("components[%d]", i)
+		switch {
+		case c.Footer != nil:
+			doc.SetFooter(c.Footer)
+		case c.Header != nil:
+			doc.Add(c.Header)
+		case c.Logo != nil:
+			doc.Add(c.Logo)
+		case c.InfoBlock != nil:
+			doc.Add(c.InfoBlock)
+		case c.Paragraph != nil:
+			doc.Add(c.Paragraph)
+		case c.SignatureBlock != nil:
+			doc.Add(c.SignatureBlock)
+		case c.QRCode != nil:
+			doc.Add(c.QRCode)
+		case c.Barcode != nil:
+			doc.Add(c.Barcode)
+		case c.RouteMap != nil:
+			doc.Add(c.RouteMap)
+		case c.AddressBlock != nil:
+			doc.Add(c.AddressBlock)
+		case c.InvoiceLines != nil:
+			doc.Add(c.InvoiceLines)
+		case c.InvoiceTotals != nil:
+			doc.Add(c.InvoiceTotals)
+		case c.Spacer != nil:
+			doc.Add(c.Spacer)
+		case c.Table != nil:
+			t, err := c.Table.build(path + ".table")
+			if err != nil {
+				return nil, err
+			}
+			doc.Add(t)
+		case c.GroupedTable != nil:
+			t, err := c.GroupedTable.Table.build(path + ".grouped_table.table")
+			if err != nil {
+				return nil, err
+			}
+			g := c.GroupedTable.GroupedTableComponent
+			g.Table = *t
+			doc.Add(&g)
+		}
+		if doc.err != nil {
+			return nil, doc.err
+		}
+	}
+	return doc, nil
+}
+
+// decode decodes the spec over base, with the theme defaulting to
+// base.Theme or DefaultTheme().
+func (s *Spec) decode(sd *specDecoder, base DocumentConfig) (*documentSpec, error) {
+	ds := &documentSpec{Page: base, Theme: base.Theme}
+	if ds.Theme.DefaultFont.Family == "" {
+		ds.Theme = DefaultTheme()
+	}
+	if s.tree == nil {
+		return ds, nil
+	}
+	if err := sd.decode("", s.tree, reflect.ValueOf(ds).Elem()); err != nil {
+		return nil, err
+	}
+	for i, c := range ds.Components {
+		path := fmt.Sprintf("components[%d]", i)
+		v := reflect.ValueOf(c)
+		set := 0
+		for j := 0; j < v.NumField(); j++ {
+			if !v.Field(j).IsNil() {
+				set++
+			}
+		}
+		if set != 1 {
+			return nil, specErr(path, "want exactly one of header, logo, info_block, paragraph, signature_block, qr_code, barcode, route_map, address_block, invoice_lines, invoice_totals, table, grouped_table, spacer or footer")
+		}
+		if c.Table != nil {
+			if err := c.Table.check(path + ".table"); err != nil {
+				return nil, err
+			}
+		}
+		if c.GroupedTable != nil {
+			if err := c.GroupedTable.Table.check(path + ".grouped_table.table"); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ds, nil
+}
+
+// check validates the columns' Field paths.
+func (s *tableSpec) check(path string) error {
+	for i, c := range s.Columns {
+		if c.Field == "" {
+			continue
+		}
+		if _, err := parsePath(c.Field); err != nil {
+			return specErr(fmt.Sprintf("%s.columns[%d].field", path, i), "%v", err)
+		}
+	}
+	return nil
+}
+
+// build returns the TableComponent with its columns and rows. A row is a
+// list of cells by column position or an object whose cells are read by
+// Field; fields missing from a row are empty cells.
+func (s *tableSpec) build(path string) (*TableComponent, error) {
+	t := s.TableComponent
+	t.Columns = make([]ColumnDef, len(s.Columns))
+	fields := make([]payloadPath, len(s.Columns))
+	for i, c := range s.Columns {
+		t.Columns[i] = c.ColumnDef
+		fields[i], _ = parsePath(c.Field)
+	}
+	if s.Rows == nil {
+		return &t, nil
+	}
+	rows, ok := s.Rows.([]any)
+	if !ok {
+		return nil, specErr(path+".rows", "want a list, got %s", specKind(s.Rows))
+	}
+	t.Data = make([][]any, len(rows))
+	for r, row := range rows {
+		rowPath := fmt.Sprintf("%s.rows[%d]", path, r)
+		cells := make([]any, len(t.Columns))
+		switch row := row.(type) {
+		case []any:
+			if len(row) > len(cells) {
+				return nil, specErr(rowPath, "has %d cells, want at most %d", len(row), len(cells))
+			}
+			copy(cells, row)
+		case map[string]any:
+			for c := range cells {
+				if fields[c] == nil {
+					return nil, specErr(fmt.Sprintf("%s.columns[%d].field", path, c), "required for object rows")
+				}
+				cells[c], _ = fields[c].lookup(row)
+			}
+		default:
+			return nil, specErr(rowPath, "want a list or an object, got %s", specKind(row))
+		}
+		for c, v := range cells {
+			cell, err := specCell(t.Columns[c], v)
+			if err != nil {
+				return nil, specErr(fmt.Sprintf("%s[%d]", rowPath, c), "%v", err)
+			}
+			cells[c] = cell
+		}
+		t.Data[r] = cells
+	}
+	return &t, nil
+}
+
+// specCell converts a payload value to a TableComponent.Data cell: numbers
+// stay typed for the column's Format and aggregates, and strings in a
+// FormatTime column are read as RFC 3339 times.
+func specCell(col ColumnDef, v any) (any, error) {
+	switch x := v.(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		if n, err := x.Float64(); err == nil {
+			return n, nil
+		}
+		return x.String(), nil
+	case float64:
+		return x, nil
+	case bool:
+		return strconv.FormatBool(x), nil
+	case string:
+		if col.Format.Kind == FormatTime {
+			if t, err := time.Parse(time.RFC3339, x); err == nil {
+				return t, nil
+			}
+		}
+		return x, nil
+	}
+	return nil, fmt.Errorf("want a cell value, got %s", specKind(v))
+}
+
+// normalizeSpec converts a decoded YAML or JSON tree to the values
+// specDecoder reads: string-keyed maps, lists, strings, float64s, booleans
+// and nils.
+func normalizeSpec(path string, v any) (any, error) {
+	switch x := v.(type) {
+	case map[string]any:
+		for k, item := range x {
+			n, err := normalizeSpec(specField(path, k), item)
+			if err != nil {
+				return nil, err
+			}
+			x[k] = n
+		}
+		return x, nil
+	case map[any]any:
+		m := make(map[string]any, len(x))
+		for k, item := range x {
+			key, ok := k.(string)
+			if !ok {
+				return nil, specErr(path, "key %v is not a string", k)
+			}
+			n, err := normalizeSpec(specField(path, key), item)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = n
+		}
+		return m, nil
+	case []any:
+		for i, item := range x {
+			n, err := normalizeSpec(fmt.Sprintf("%s[%d]", path, i), item)
+			if err != nil {
+				return nil, err
+			}
+			x[i] = n
+		}
+		return x, nil
+	case int:
+		return float64(x), nil
+	case int64:
+		return float64(x), nil
+	case uint64:
+		return float64(x), nil
+	case time.Time:
+		return x.Format(time.RFC3339), nil
+	}
+	return v, nil
+}
@@ -0,0 +1,149 @@
+package pdfgen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSpecErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string // the error, or its start when it ends in a space
+	}{
+		{
+			name: "column width type",
+			src: `components:
+  - info_block: {}
+  - spacer: {height: 4}
+  - header: {title: Log}
+  - table:
+      columns:
+        - {header: Date}
+        - {header: Miles, width: wide}`,
+			want: `pdfgen: spec: components[3].table.columns[1].width: want a number, got string "wide"`,
+		},
+		{
+			name: "unknown top-level field",
+			src:  `pages: {orientation: landscape}`,
+			want: `pdfgen: spec: pages: unknown field`,
+		},
+		{
+			name: "unknown component field",
+			src:  `{"components": [{"header": {"title": "Log", "subtitel": "Q4"}}]}`,
+			want: `pdfgen: spec: components[0].header.subtitel: unknown field`,
+		},
+		{
+			name: "field replaced by rows",
+			src:  `components: [{table: {data: [[1]]}}]`,
+			want: `pdfgen: spec: components[0].table.data: unknown field`,
+		},
+		{
+			name: "boolean type",
+			src:  `components: [{table: {show_header: "yes"}}]`,
+			want: `pdfgen: spec: components[0].table.show_header: want a boolean, got string "yes"`,
+		},
+		{
+			name: "list type",
+			src:  `components: [{header: {lines: Q4}}]`,
+			want: `pdfgen: spec: components[0].header.lines: want a list, got string "Q4"`,
+		},
+		{
+			name: "enum value",
+			src:  `components: [{table: {columns: [{header: Miles, aggregate: total}]}}]`,
+			want: `pdfgen: spec: components[0].table.columns[0].aggregate: unknown value "total"; want one of avg, count, max, min, none, sum`,
+		},
+		{
+			name: "color",
+			src:  `theme: {accent_color: blue}`,
+			want: `pdfgen: spec: theme.accent_color: invalid color "blue"; want "#RRGGBB"`,
+		},
+		{
+			name: "no block",
+			src:  `components: [{}]`,
+			want: `pdfgen: spec: components[0]: want exactly one of header, logo, info_block, `,
+		},
+		{
+			name: "two blocks",
+			src:  `components: [{spacer: {height: 4}}, {header: {title: Log}, spacer: {height: 4}}]`,
+			want: `pdfgen: spec: components[1]: want exactly one of header, logo, info_block, `,
+		},
+		{
+			name: "column field path",
+			src:  `components: [{table: {columns: [{header: Start, field: "odometer[x]"}]}}]`,
+			want: `pdfgen: spec: components[0].table.columns[0].field: invalid index in path "odometer[x]"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSpec([]byte(tt.src))
+			if err == nil {
+				t.Fatalf("ParseSpec: want error %q", tt.want)
+			}
+			if got := err.Error(); got != tt.want && !(strings.HasSuffix(tt.want, " ") && strings.HasPrefix(got, tt.want)) {
+				t.Errorf("error\n got %s\nwant %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpecBuildErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		payload string
+		want    string
+	}{
+		{
+			name:    "missing binding",
+			src:     `components: [{header: {title: "${carrier.name}"}}]`,
+			payload: `{"carrier": {}}`,
+			want:    `pdfgen: spec: components[0].header.title: payload has no "carrier.name"`,
+		},
+		{
+			name:    "missing binding in text",
+			src:     `components: [{header: {lines: ["Q${quarter} ${year}"]}}]`,
+			payload: `{"quarter": 4}`,
+			want:    `pdfgen: spec: components[0].header.lines[0]: payload has no "year"`,
+		},
+		{
+			name:    "missing rows",
+			src:     `components: [{table: {columns: [{header: State, field: state}], rows: "${trips}"}}]`,
+			payload: `{}`,
+			want:    `pdfgen: spec: components[0].table.rows: payload has no "trips"`,
+		},
+		{
+			name:    "rows not a list",
+			src:     `components: [{table: {columns: [{header: State, field: state}], rows: "${trips}"}}]`,
+			payload: `{"trips": {"state": "TN"}}`,
+			want:    `pdfgen: spec: components[0].table.rows: want a list, got an object`,
+		},
+		{
+			name:    "object row without field",
+			src:     `components: [{table: {columns: [{header: State}], rows: "${trips}"}}]`,
+			payload: `{"trips": [{"state": "TN"}]}`,
+			want:    `pdfgen: spec: components[0].table.columns[0].field: required for object rows`,
+		},
+		{
+			name:    "cell type",
+			src:     `components: [{grouped_table: {table: {columns: [{header: State}], rows: "${trips}"}}}]`,
+			payload: `{"trips": [[{"state": "TN"}]]}`,
+			want:    `pdfgen: spec: components[0].grouped_table.table.rows[0][0]: want a cell value, got an object`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := ParseSpec([]byte(tt.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = s.Build(DocumentConfig{}, []byte(tt.payload))
+			if err == nil {
+				t.Fatalf("Build: want error %q", tt.want)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("error\n got %s\nwant %s", err, tt.want)
+			}
+		})
+	}
+}
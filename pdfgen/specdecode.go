@@ -0,0 +1,494 @@
+package pdfgen
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// specDecoder decodes a parsed spec — maps, lists, strings, float64s,
+// booleans and nils — into Go values by reflection. Struct fields are keyed
+// by their names in snake_case, e.g. MarginTop → margin_top. Errors carry
+// the path of the offending value, e.g. components[3].table.columns[1].width.
+type specDecoder struct {
+	data any  // JSON payload that ${path} bindings read from
+	bind bool // resolve bindings; false while ParseSpec validates the spec
+}
+
+// binding matches ${path} in spec strings.
+var binding = regexp.MustCompile(`\$\{([^}]*)\}`)
+
+// specHidden lists exported fields a spec cannot set: outputs, and fields
+// replaced by the spec's own data binding.
+var specHidden = map[string]bool{
+	"DocumentConfig.Theme":            true,
+	"DocumentConfig.Fonts":            true,
+	"DocumentConfig.Date":             true,
+	"DocumentConfig.MissingGlyph":     true,
+	"TableComponent.Data":             true,
+	"TableComponent.TruncatedColumns": true,
+}
+
+// specEnums names the values of the enum types a spec can set.
+var specEnums = map[reflect.Type]map[string]int64{
+	reflect.TypeOf(OverflowMode(0)): {"wrap": int64(OverflowWrap), "truncate": int64(OverflowTruncate)},
+	reflect.TypeOf(AggregateFunc(0)): {
+		"none": int64(AggregateNone), "sum": int64(AggregateSum), "avg": int64(AggregateAvg),
+		"min": int64(AggregateMin), "max": int64(AggregateMax), "count": int64(AggregateCount),
+	},
+	reflect.TypeOf(FormatKind(0)): {
+		"none": int64(FormatNone), "number": int64(FormatNumber), "currency": int64(FormatCurrency),
+		"distance": int64(FormatDistance), "time": int64(FormatTime), "duration": int64(FormatDuration),
+	},
+	reflect.TypeOf(DistanceUnit(0)): {"meters": int64(Meters), "kilometers": int64(Kilometers), "miles": int64(Miles)},
+	reflect.TypeOf(CompareOp(0)): {
+		"none": int64(CompareNone), "gt": int64(CompareGT), "ge": int64(CompareGE), "lt": int64(CompareLT),
+		"le": int64(CompareLE), "eq": int64(CompareEQ), "ne": int64(CompareNE),
+	},
+	reflect.TypeOf(QRLevel(0)):     {"L": int64(QRLevelL), "M": int64(QRLevelM), "Q": int64(QRLevelQ), "H": int64(QRLevelH)},
+	reflect.TypeOf(BarcodeType(0)): {"code128": int64(Code128), "code39": int64(Code39)},
+	reflect.TypeOf(MapProjection(0)): {
+		"mercator": int64(ProjectionMercator), "equirectangular": int64(ProjectionEquirectangular),
+	},
+	reflect.TypeOf(Icon(0)): {
+		"none": int64(IconNone), "dot": int64(IconDot), "warning": int64(IconWarning), "arrow_up": int64(IconArrowUp),
+		"arrow_down": int64(IconArrowDown), "check": int64(IconCheck), "cross": int64(IconCross),
+	},
+}
+
+var (
+	colorType    = reflect.TypeOf(Color{})
+	locationType = reflect.TypeOf((*time.Location)(nil))
+	timeType     = reflect.TypeOf(time.Time{})
+	bytesType    = reflect.TypeOf([]byte(nil))
+)
+
+func specErr(path, format string, args ...any) error {
+	return fmt.Errorf("pdfgen: spec: %s: %s", path, fmt.Sprintf(format, args...))
+}
+
+func specField(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// decode stores v in dst, which must be settable. Fields of a struct that v
+// does not mention keep their value, so dst may hold defaults.
+func (sd *specDecoder) decode(path string, v any, dst reflect.Value) error {
+	if s, ok := v.(string); ok && strings.Contains(s, "${") {
+		m := binding.FindStringSubmatchIndex(s)
+		whole := m != nil && m[0] == 0 && m[1] == len(s)
+		if dst.Kind() != reflect.String && whole {
+			if !sd.bind {
+				_, err := parsePath(s[m[2]:m[3]])
+				if err != nil {
+					return specErr(path, "%v", err)
+				}
+				return nil
+			}
+			bound, err := sd.lookup(path, s[m[2]:m[3]])
+			if err != nil {
+				return err
+			}
+			v = bound
+		} else if dst.Kind() == reflect.String {
+			text, err := sd.interpolate(path, s)
+			if err != nil {
+				return err
+			}
+			v = text
+		}
+	}
+
+	t := dst.Type()
+	if names, ok := specEnums[t]; ok {
+		s, ok := v.(string)
+		if !ok {
+			return specErr(path, "want a string, got %s", specKind(v))
+		}
+		n, ok := names[s]
+		if !ok {
+			return specErr(path, "unknown value %q; want one of %s", s, enumNames(names))
+		}
+		dst.SetInt(n)
+		return nil
+	}
+	switch t {
+	case colorType:
+		if s, ok := v.(string); ok {
+			c, err := parseHexColor(s)
+			if err != nil {
+				return specErr(path, "%v", err)
+			}
+			dst.Set(reflect.ValueOf(c))
+			return nil
+		}
+	case locationType:
+		s, ok := v.(string)
+		if !ok {
+			return specErr(path, "want a time zone name, got %s", specKind(v))
+		}
+		loc, err := time.LoadLocation(s)
+		if err != nil {
+			return specErr(path, "unknown time zone %q", s)
+		}
+		dst.Set(reflect.ValueOf(loc))
+		return nil
+	case timeType:
+		s, ok := v.(string)
+		if !ok {
+			return specErr(path, "want an RFC 3339 time, got %s", specKind(v))
+		}
+		tm, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return specErr(path, "want an RFC 3339 time, got %q", s)
+		}
+		dst.Set(reflect.ValueOf(tm))
+		return nil
+	case bytesType:
+		s, ok := v.(string)
+		if !ok {
+			return specErr(path, "want a base64 string, got %s", specKind(v))
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return specErr(path, "invalid base64: %v", err)
+		}
+		dst.SetBytes(b)
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Interface:
+		if v != nil {
+			dst.Set(reflect.ValueOf(v))
+		}
+		return nil
+	case reflect.Pointer:
+		if v == nil {
+			dst.SetZero()
+			return nil
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.New(t.Elem()))
+		}
+		return sd.decode(path, v, dst.Elem())
+	case reflect.String:
+		s, ok := v.(string)
+		if !ok {
+			return specErr(path, "want a string, got %s", specKind(v))
+		}
+		dst.SetString(s)
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return specErr(path, "want a boolean, got %s", specKind(v))
+		}
+		dst.SetBool(b)
+	case reflect.Float32, reflect.Float64:
+		n, ok := specNumber(v)
+		if !ok {
+			return specErr(path, "want a number, got %s", specKind(v))
+		}
+		dst.SetFloat(n)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := specNumber(v)
+		if !ok || n != float64(int64(n)) {
+			return specErr(path, "want an integer, got %s", specKind(v))
+		}
+		dst.SetInt(int64(n))
+	case reflect.Slice:
+		list, ok := v.([]any)
+		if !ok {
+			return specErr(path, "want a list, got %s", specKind(v))
+		}
+		s := reflect.MakeSlice(t, len(list), len(list))
+		for i, item := range list {
+			if err := sd.decode(fmt.Sprintf("%s[%d]", path, i), item, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		dst.Set(s)
+	case reflect.Map:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return specErr(path, "want an object, got %s", specKind(v))
+		}
+		// Copy so a map of DocumentConfig defaults is left as it was.
+		m := reflect.MakeMap(t)
+		for it := dst.MapRange(); it.Next(); {
+			m.SetMapIndex(it.Key(), it.Value())
+		}
+		dst.Set(m)
+		for _, k := range sortedKeys(obj) {
+			item := reflect.New(t.Elem()).Elem()
+			if err := sd.decode(specField(path, k), obj[k], item); err != nil {
+				return err
+			}
+			dst.SetMapIndex(reflect.ValueOf(k), item)
+		}
+	case reflect.Struct:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return specErr(path, "want an object, got %s", specKind(v))
+		}
+		fields := specFields(t)
+		for _, k := range sortedKeys(obj) {
+			index, ok := fields[k]
+			if !ok {
+				return specErr(specField(path, k), "unknown field")
+			}
+			if err := sd.decode(specField(path, k), obj[k], dst.FieldByIndex(index)); err != nil {
+				return err
+			}
+		}
+	default:
+		return specErr(path, "cannot be set from a spec")
+	}
+	return nil
+}
+
+// lookup resolves the binding at path to its payload value.
+func (sd *specDecoder) lookup(path, expr string) (any, error) {
+	p, err := parsePath(expr)
+	if err != nil {
+		return nil, specErr(path, "%v", err)
+	}
+	v, ok := p.lookup(sd.data)
+	if !ok {
+		return nil, specErr(path, "payload has no %q", expr)
+	}
+	return v, nil
+}
+
+// interpolate replaces the bindings in s with their payload values as text.
+func (sd *specDecoder) interpolate(path, s string) (string, error) {
+	var err error
+	out := binding.ReplaceAllStringFunc(s, func(m string) string {
+		expr := m[2 : len(m)-1]
+		if !sd.bind {
+			if _, perr := parsePath(expr); perr != nil && err == nil {
+				err = specErr(path, "%v", perr)
+			}
+			return m
+		}
+		v, lerr := sd.lookup(path, expr)
+		if lerr != nil {
+			if err == nil {
+				err = lerr
+			}
+			return m
+		}
+		text, ok := specText(v)
+		if !ok && err == nil {
+			err = specErr(path, "%q is %s, want text", expr, specKind(v))
+		}
+		return text
+	})
+	return out, err
+}
+
+// specText formats a payload scalar as text.
+func specText(v any) (string, bool) {
+	switch x := v.(type) {
+	case nil:
+		return "", true
+	case string:
+		return x, true
+	case json.Number:
+		return x.String(), true
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(x), true
+	}
+	return "", false
+}
+
+func specNumber(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case json.Number:
+		n, err := x.Float64()
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// specKind names the kind of a spec or payload value for error messages.
+func specKind(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "a boolean"
+	case float64, json.Number:
+		return "a number"
+	case string:
+		return fmt.Sprintf("string %q", x)
+	case []any:
+		return "a list"
+	case map[string]any:
+		return "an object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func enumNames(names map[string]int64) string {
+	keys := make([]string, 0, len(names))
+	for k := range names {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return strings.Join(keys, ", ")
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// parseHexColor parses "#RRGGBB" or "#RGB".
+func parseHexColor(s string) (Color, error) {
+	h := strings.TrimPrefix(s, "#")
+	if len(h) == 3 {
+		h = string([]byte{h[0], h[0], h[1], h[1], h[2], h[2]})
+	}
+	n, err := strconv.ParseUint(h, 16, 32)
+	if len(h) != 6 || err != nil || !strings.HasPrefix(s, "#") {
+		return Color{}, fmt.Errorf("invalid color %q; want \"#RRGGBB\"", s)
+	}
+	return Color{R: int(n >> 16), G: int(n >> 8 & 0xFF), B: int(n & 0xFF)}, nil
+}
+
+var specFieldCache sync.Map // reflect.Type → map[string][]int
+
+// specFields returns the spec keys of struct type t and their field
+// indexes. Embedded structs are inlined; fields of the outer struct shadow
+// theirs. Funcs, interfaces with methods and specHidden fields are left out.
+func specFields(t reflect.Type) map[string][]int {
+	if f, ok := specFieldCache.Load(t); ok {
+		return f.(map[string][]int)
+	}
+	fields := make(map[string][]int)
+	var embedded []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			embedded = append(embedded, f)
+			continue
+		}
+		if !f.IsExported() || specHidden[t.Name()+"."+f.Name] {
+			continue
+		}
+		switch f.Type.Kind() {
+		case reflect.Func, reflect.Chan:
+			continue
+		case reflect.Interface:
+			if f.Type.NumMethod() > 0 {
+				continue
+			}
+		}
+		fields[snakeCase(f.Name)] = f.Index
+	}
+	for _, e := range embedded {
+		for k, index := range specFields(e.Type) {
+			if _, ok := fields[k]; !ok {
+				fields[k] = append(append([]int(nil), e.Index...), index...)
+			}
+		}
+	}
+	specFieldCache.Store(t, fields)
+	return fields
+}
+
+// snakeCase converts a Go field name to its spec key, e.g. ReportID →
+// report_id and CellPaddingH → cell_padding_h.
+func snakeCase(name string) string {
+	r := []rune(name)
+	var b strings.Builder
+	for i, c := range r {
+		if i > 0 && unicode.IsUpper(c) {
+			prev := r[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (i+1 < len(r) && unicode.IsLower(r[i+1])) {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteRune(unicode.ToLower(c))
+	}
+	return b.String()
+}
+
+// payloadPath is a parsed path expression: object keys (string) and list
+// indexes (int), e.g. "trips[0].miles" → ["trips", 0, "miles"].
+type payloadPath []any
+
+// parsePath parses a path expression such as "driver.name" or
+// "trips[0].miles".
+func parsePath(s string) (payloadPath, error) {
+	var p payloadPath
+	for _, seg := range strings.Split(s, ".") {
+		key := seg
+		if i := strings.IndexByte(seg, '['); i >= 0 {
+			key = seg[:i]
+		}
+		if key != "" {
+			p = append(p, key)
+		}
+		rest := seg[len(key):]
+		if key == "" && rest == "" {
+			return nil, fmt.Errorf("invalid path %q", s)
+		}
+		for rest != "" {
+			end := strings.IndexByte(rest, ']')
+			if rest[0] != '[' || end < 0 {
+				return nil, fmt.Errorf("invalid path %q", s)
+			}
+			n, err := strconv.Atoi(rest[1:end])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid index in path %q", s)
+			}
+			p = append(p, n)
+			rest = rest[end+1:]
+		}
+	}
+	return p, nil
+}
+
+// lookup returns the value at p in v.
+func (p payloadPath) lookup(v any) (any, bool) {
+	for _, seg := range p {
+		switch k := seg.(type) {
+		case string:
+			obj, ok := v.(map[string]any)
+			if !ok {
+				return nil, false
+			}
+			if v, ok = obj[k]; !ok {
+				return nil, false
+			}
+		case int:
+			list, ok := v.([]any)
+			if !ok || k >= len(list) {
+				return nil, false
+			}
+			v = list[k]
+		}
+	}
+	return v, true
+}
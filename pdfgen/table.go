@@ -12,12 +12,20 @@ const (
 
 // ColumnDef defines a single table column.
 type ColumnDef struct {
-	Header      string
-	Width       float64      // mm; 0 = column shares remaining space equally
-	Align       string       // "L", "C", "R"
-	Overflow    OverflowMode // per-column overflow handling
-	HeaderAlign string       // defaults to Align if empty
-	Bold        bool         // render cell content bold
+	Header          string
+	Width           float64                        // mm; 0 = column shares remaining space equally
+	MinWidth        float64                        // mm; lower bound for AutoWidth
+	MaxWidth        float64                        // mm; upper bound for AutoWidth; 0 = none
+	Flex            float64                        // share of the width left to Width == 0 columns, as CSS flex; 0 = 1
+	Align           string                         // "L", "C", "R"
+	Overflow        OverflowMode                   // per-column overflow handling
+	HeaderAlign     string                         // defaults to Align if empty
+	Bold            bool                           // render cell content bold
+	Aggregate       AggregateFunc                  // footer summary over the column's numeric cells
+	AggregateCustom func(values []float64) float64 // used when Aggregate is AggregateCustom
+	AggregateFormat func(float64) string           // zero value → Format, or decimals and unit of the column's cells
+	Format          ValueFormat                    // formats typed values in Data or Source; numeric formats align "R"
+	FormatFunc      func(v any) string             // custom formatter for typed values; overrides Format
 }
 
 // TableComponent renders a structured data table with optional header, striping,
@@ -31,9 +39,23 @@ type ColumnDef struct {
 //   "outer"   — border around each row only
 //   "columns" — outer border for the whole table + column separators + header bottom line (matches Lucid ELD HTML design)
 //   "none"    — no borders
+//
+// Footer: when any column sets Aggregate, a footer row with the computed
+// values is rendered after the last data row and kept on the same page as it.
+// With CarryForward, a "Carried forward" row closes every page the table
+// breaks across and a "Brought forward" row opens the next one, both showing
+// the running aggregates of all rows so far.
+//
+// Grouping: GroupBy lists key column indexes. Consecutive rows with equal
+// keys form a group (sort Rows by the keys first) that opens with a header
+// band, e.g. "7070 · 1HGCM82633A123456", and optionally closes with a
+// subtotal row. A group split across pages repeats its header band, marked
+// " (continued)", below the repeated column header.
 type TableComponent struct {
 	Columns      []ColumnDef
 	Rows         [][]string
+	Data         [][]any      // rows of string and Cell values; used instead of Rows when non-nil
+	Source       RowSource    // streamed rows; used instead of Data and Rows when non-nil
 	ShowHeader   bool         // render the column header row
 	RowStriping  bool         // alternate row background colors
 	CellPaddingH float64      // horizontal cell padding mm; default 3
@@ -42,6 +64,45 @@ type TableComponent struct {
 	HeaderFont   FontConfig   // zero value → theme default, bold
 	RowFont      FontConfig   // zero value → theme default
 	MinRowHeight float64      // mm; default 8
+
+	FooterLabel         string     // text of the first non-aggregated footer cell; default "Total"
+	FooterFont          FontConfig // zero value → RowFont, bold
+	CarryForward        bool       // add carried/brought forward subtotal rows at page breaks
+	CarryForwardLabel   string     // default "Carried forward"
+	BroughtForwardLabel string     // default "Brought forward"
+
+	GroupBy          []int                     // key column indexes; empty = no grouping
+	GroupLabel       func(key []string) string // group header text; nil → key values joined with " · "
+	HideGroupColumns bool                      // drop the GroupBy columns from the rows
+	GroupSubtotals   bool                      // subtotal row after each group; needs Aggregate columns
+	SubtotalLabel    string                    // default "Subtotal"
+	GroupPageBreak   bool                      // start every group after the first on a new page
+	GroupFont        FontConfig                // zero value → RowFont, bold
+	ContinuedLabel   string                    // appended to a repeated group header; default " (continued)"
+
+	HeaderGroups [][]HeaderGroup // header rows above the column headers, top row first
+
+	AutoWidth        bool  // size Width == 0 columns to their header and cell text
+	AutoWidthRows    int   // rows measured by AutoWidth; default 1000
+	TruncatedColumns []int // set by Render with AutoWidth: columns narrower than their content
+
+	Rules []FormatRule // conditional row and cell styles, evaluated in order
+}
+
+// tableLayout holds the resolved geometry, fonts and border mode of one Render call.
+type tableLayout struct {
+	widths      []float64
+	paddingH    float64
+	paddingV    float64
+	minRowH     float64
+	lineH       float64
+	borderStyle string
+	headerFont  FontConfig
+	rowFont     FontConfig
+	footerFont  FontConfig
+	groupFont   FontConfig
+	header      []headerCell // resolved header cells, including HeaderGroups
+	headerRows  int
 }
 
 // Render draws the table and advances the Y cursor.
@@ -50,6 +111,176 @@ func (t *TableComponent) Render(doc *Document) error {
 		return nil
 	}
 
+	if err := t.checkGroupBy(); err != nil {
+		return err
+	}
+	rules, err := t.compileRules()
+	if err != nil {
+		return err
+	}
+	rows := &rowReader{t: t, rules: rules}
+	defer rows.close()
+	if len(t.GroupBy) > 0 && t.HideGroupColumns {
+		var view *TableComponent
+		view, rows.hidden = t.withoutGroupColumns()
+		err := view.render(doc, rows)
+		// Report truncated columns by their index in t.Columns.
+		t.TruncatedColumns = nil
+		for _, v := range view.TruncatedColumns {
+			for i := range t.Columns {
+				if !rows.hidden[i] {
+					if v == 0 {
+						t.TruncatedColumns = append(t.TruncatedColumns, i)
+						break
+					}
+					v--
+				}
+			}
+		}
+		return err
+	}
+	return t.render(doc, rows)
+}
+
+// render draws the rows, group headers and the header on every page, and
+// the subtotal, carried-forward and footer rows. Rows are read one ahead of
+// the one being drawn, so only two rows are held in memory.
+func (t *TableComponent) render(doc *Document, rows *rowReader) error {
+	var sample [][]Cell
+	if t.AutoWidth {
+		n := t.AutoWidthRows
+		if n == 0 {
+			n = 1000
+		}
+		var err error
+		if sample, err = rows.peek(n); err != nil {
+			return err
+		}
+	}
+	lay, err := t.layout(doc, sample)
+	if err != nil {
+		return err
+	}
+	aggs := newTableAggregates(t.Columns)
+	hasFooter := aggs.active()
+	subtotals := t.GroupSubtotals && hasFooter
+	summaryH := lay.minRowH
+	subtotalLabel := t.SubtotalLabel
+	if subtotalLabel == "" {
+		subtotalLabel = "Subtotal"
+	}
+
+	cur, err := rows.next()
+	if err != nil {
+		return err
+	}
+	if cur == nil {
+		// An empty table still shows its header, with the footer below it.
+		need := t.headerHeight(lay)
+		if hasFooter {
+			need += summaryH
+		}
+		doc.newPageIfNeeded(need)
+		t.renderHeader(doc, lay)
+	}
+	first := cur != nil
+	var sub *tableAggregates
+	label := ""
+	i := 0 // row index within the current group
+	for cur != nil {
+		next, err := rows.next()
+		if err != nil {
+			return err
+		}
+		isLast := next == nil
+		lastInGroup := isLast || !equalKeys(cur.key, next.key)
+		if i == 0 {
+			sub = newTableAggregates(t.Columns)
+			label = ""
+			if cur.key != nil {
+				label = t.groupLabel(cur.key)
+			}
+		}
+
+		bgColor := doc.theme.TableRowOddBg
+		if t.RowStriping && i%2 == 0 {
+			bgColor = doc.theme.TableRowEvenBg
+		}
+
+		rowH := t.calcRowHeight(doc, cur.cells, lay.widths, lay.paddingH, lay.paddingV, lay.lineH, lay.rowFont)
+		if rowH < lay.minRowH {
+			rowH = lay.minRowH
+		}
+
+		// Space this row needs: the subtotal and footer travel with the
+		// last row, and every other row leaves room for a carried-forward
+		// row below it.
+		need := rowH
+		if subtotals && lastInGroup {
+			need += summaryH
+		}
+		if hasFooter && isLast {
+			need += summaryH
+		}
+		if t.CarryForward && hasFooter && !isLast {
+			need += summaryH
+		}
+		// A group header is kept with the group's first row.
+		lead := 0.0
+		if i == 0 && label != "" {
+			lead = lay.minRowH
+		}
+
+		switch {
+		case first:
+			// Keep the header together with the first row.
+			doc.newPageIfNeeded(t.headerHeight(lay) + lead + need)
+			t.renderHeader(doc, lay)
+			first = false
+		case i == 0 && t.GroupPageBreak:
+			t.pageBreak(doc, lay, aggs)
+		case doc.currentY()+lead+need > doc.pageBottom():
+			t.pageBreak(doc, lay, aggs)
+			if i > 0 && label != "" {
+				continued := t.ContinuedLabel
+				if continued == "" {
+					continued = " (continued)"
+				}
+				t.renderGroupHeader(doc, lay, label+continued)
+			}
+		}
+		if i == 0 && label != "" {
+			t.renderGroupHeader(doc, lay, label)
+		}
+
+		t.renderBodyRow(doc, lay, cur.cells, bgColor, rowH, lay.rowFont)
+		aggs.add(cur.cells)
+		sub.add(cur.cells)
+
+		i++
+		if lastInGroup {
+			if subtotals {
+				t.renderSummaryRow(doc, lay, sub.row(subtotalLabel))
+			}
+			i = 0
+		}
+		cur = next
+	}
+
+	if hasFooter {
+		label := t.FooterLabel
+		if label == "" {
+			label = "Total"
+		}
+		t.renderSummaryRow(doc, lay, aggs.row(label))
+	}
+
+	return nil
+}
+
+// layout resolves padding, fonts, border style, column widths and header
+// cells. sample holds the rows AutoWidth measures.
+func (t *TableComponent) layout(doc *Document, sample [][]Cell) (*tableLayout, error) {
 	paddingH := t.CellPaddingH
 	if paddingH == 0 {
 		paddingH = 2.8
@@ -83,82 +314,94 @@ func (t *TableComponent) Render(doc *Document) error {
 	if rowFont.Family == "" {
 		rowFont = doc.theme.DefaultFont
 	}
+	footerFont := t.FooterFont
+	if footerFont.Family == "" {
+		footerFont = rowFont
+		footerFont.Style = "B"
+	}
+	groupFont := t.GroupFont
+	if groupFont.Family == "" {
+		groupFont = rowFont
+		groupFont.Style = "B"
+	}
 
 	widths := t.resolveColumnWidths(doc.usableWidth())
-
-	// "columns" style: outer border + column separators + header bottom line.
-	// Borders are drawn per-page-section after rows are rendered.
-	if borderStyle == "columns" {
-		return t.renderColumnsStyle(doc, widths, paddingH, paddingV, minRowH, lineH, headerFont, rowFont)
+	header, headerRows, err := t.headerCells(widths)
+	if err != nil {
+		return nil, err
 	}
-
-	if t.ShowHeader {
-		t.renderHeaderRow(doc, widths, paddingH, paddingV, minRowH, headerFont, borderStyle)
+	if t.AutoWidth {
+		widths, t.TruncatedColumns = t.autoColumnWidths(doc, doc.usableWidth(), sample, header, headerFont, rowFont, paddingH)
+		header, _, _ = t.headerCells(widths)
 	}
 
-	for i, row := range t.Rows {
-		bgColor := doc.theme.TableRowOddBg
-		if t.RowStriping && i%2 == 0 {
-			bgColor = doc.theme.TableRowEvenBg
-		}
+	return &tableLayout{
+		widths:      widths,
+		paddingH:    paddingH,
+		paddingV:    paddingV,
+		minRowH:     minRowH,
+		lineH:       lineH,
+		borderStyle: borderStyle,
+		headerFont:  headerFont,
+		rowFont:     rowFont,
+		footerFont:  footerFont,
+		groupFont:   groupFont,
+		header:      header,
+		headerRows:  headerRows,
+	}, nil
+}
 
-		rowH := t.calcRowHeight(doc, row, widths, paddingH, paddingV, lineH, rowFont)
-		if rowH < minRowH {
-			rowH = minRowH
+// pageBreak closes the current page and opens the next one, repeating the
+// header and, with CarryForward, the running subtotals on both sides.
+func (t *TableComponent) pageBreak(doc *Document, lay *tableLayout, aggs *tableAggregates) {
+	carry := t.CarryForward && aggs.active()
+	if carry {
+		label := t.CarryForwardLabel
+		if label == "" {
+			label = "Carried forward"
 		}
-
-		added := doc.newPageIfNeeded(rowH)
-		if added && t.ShowHeader {
-			t.renderHeaderRow(doc, widths, paddingH, paddingV, minRowH, headerFont, borderStyle)
+		t.renderSummaryRow(doc, lay, aggs.row(label))
+	}
+	doc.addPage()
+	t.renderHeader(doc, lay)
+	if carry {
+		label := t.BroughtForwardLabel
+		if label == "" {
+			label = "Brought forward"
 		}
-
-		t.renderDataRow(doc, row, bgColor, widths, paddingH, paddingV, rowH, lineH, rowFont, borderStyle)
+		t.renderSummaryRow(doc, lay, aggs.row(label))
 	}
-
-	return nil
 }
 
-// renderColumnsStyle renders with the "columns" border mode:
-//   - Outer rect + column separator lines drawn per row
-//   - Horizontal line below the header row
-//   - No horizontal lines between data rows
-func (t *TableComponent) renderColumnsStyle(doc *Document, widths []float64, paddingH, paddingV, minRowH, lineH float64, headerFont, rowFont FontConfig) error {
-	startX := doc.marginL
-
-	if t.ShowHeader {
-		t.renderColumnsRow(doc, nil, true, doc.theme.TableHeaderBg, widths, paddingH, paddingV, minRowH, lineH, headerFont)
-		// Header bottom separator line.
-		doc.applyColor(doc.theme.TableBorderColor)
-		doc.pdf.Line(startX, doc.currentY(), startX+doc.usableWidth(), doc.currentY())
+// headerHeight returns the height of the header rows, or 0 when hidden.
+func (t *TableComponent) headerHeight(lay *tableLayout) float64 {
+	if !t.ShowHeader {
+		return 0
 	}
+	return float64(lay.headerRows) * lay.minRowH
+}
 
-	for i, row := range t.Rows {
-		bgColor := doc.theme.TableRowOddBg
-		if t.RowStriping && i%2 == 0 {
-			bgColor = doc.theme.TableRowEvenBg
-		}
-
-		rowH := t.calcRowHeight(doc, row, widths, paddingH, paddingV, lineH, rowFont)
-		if rowH < minRowH {
-			rowH = minRowH
-		}
-
-		added := doc.newPageIfNeeded(rowH)
-		if added && t.ShowHeader {
-			t.renderColumnsRow(doc, nil, true, doc.theme.TableHeaderBg, widths, paddingH, paddingV, minRowH, lineH, headerFont)
-			doc.applyColor(doc.theme.TableBorderColor)
-			doc.pdf.Line(startX, doc.currentY(), startX+doc.usableWidth(), doc.currentY())
-		}
-
-		t.renderColumnsRow(doc, row, false, bgColor, widths, paddingH, paddingV, rowH, lineH, rowFont)
+// renderBodyRow draws one data row in the table's border style.
+func (t *TableComponent) renderBodyRow(doc *Document, lay *tableLayout, row []Cell, bgColor Color, rowH float64, font FontConfig) {
+	if lay.borderStyle == "columns" {
+		t.renderColumnsRow(doc, row, bgColor, lay.widths, lay.paddingH, lay.paddingV, rowH, lay.lineH, font)
+		return
 	}
+	t.renderDataRow(doc, row, bgColor, lay.widths, lay.paddingH, lay.paddingV, rowH, lay.lineH, font, lay.borderStyle)
+}
 
-	return nil
+// renderSummaryRow draws a footer or carried/brought forward row: header
+// background, footer font, and in "columns" style a separator line above it.
+func (t *TableComponent) renderSummaryRow(doc *Document, lay *tableLayout, cells []string) {
+	if lay.borderStyle == "columns" {
+		doc.applyColor(doc.theme.TableBorderColor)
+		doc.pdf.Line(doc.marginL, doc.currentY(), doc.marginL+doc.usableWidth(), doc.currentY())
+	}
+	t.renderBodyRow(doc, lay, textCells(cells), doc.theme.TableHeaderBg, lay.minRowH, lay.footerFont)
 }
 
 // renderColumnsRow draws one row with per-row outer rect + column separators.
-// Pass isHeader=true and row=nil to render the header row.
-func (t *TableComponent) renderColumnsRow(doc *Document, row []string, isHeader bool, bgColor Color, widths []float64, paddingH, paddingV, rowH, lineH float64, font FontConfig) {
+func (t *TableComponent) renderColumnsRow(doc *Document, row []Cell, bgColor Color, widths []float64, paddingH, paddingV, rowH, lineH float64, font FontConfig) {
 	startY := doc.currentY()
 	startX := doc.marginL
 	totalW := doc.usableWidth()
@@ -168,90 +411,46 @@ func (t *TableComponent) renderColumnsRow(doc *Document, row []string, isHeader
 	doc.pdf.Rect(startX, startY, totalW, rowH, "F")
 
 	// Step 2: Draw cell text (no border).
-	if isHeader {
-		doc.applyFont(font)
-		doc.applyTextColor(doc.theme.HeaderTextColor)
-		x := startX
-		for i, col := range t.Columns {
-			align := col.HeaderAlign
-			if align == "" {
-				align = col.Align
-			}
-			if align == "" {
-				align = "L"
-			}
-			doc.pdf.SetXY(x+paddingH, startY+paddingV)
-			doc.pdf.CellFormat(widths[i]-2*paddingH, rowH-2*paddingV, col.Header, "", 0, align, false, 0, "")
-			x += widths[i]
-		}
-	} else {
-		x := startX
-		for i, col := range t.Columns {
-			text := ""
-			if i < len(row) {
-				text = row[i]
-			}
-			align := col.Align
-			if align == "" {
-				align = "L"
-			}
-			cellFont := font
-			if col.Bold {
-				cellFont.Style = "B"
-			}
-			doc.applyFont(cellFont)
-			doc.applyTextColor(doc.theme.PrimaryText)
-
-			cellW := widths[i] - 2*paddingH
-
-			if col.Overflow == OverflowWrap {
-				doc.pdf.SetXY(x+paddingH, startY+paddingV)
-				doc.pdf.MultiCell(cellW, lineH, text, "", align, false)
-			} else {
-				if col.Overflow == OverflowTruncate {
-					text = truncateText(doc, text, cellW)
-				}
-				doc.pdf.SetXY(x+paddingH, startY+paddingV)
-				doc.pdf.CellFormat(cellW, rowH-2*paddingV, text, "", 0, align, false, 0, "")
-			}
-			x += widths[i]
+	x := startX
+	for i := 0; i < len(t.Columns); {
+		var cell Cell
+		if i < len(row) {
+			cell = row[i]
 		}
+		span := cellSpan(cell, i, len(t.Columns))
+		w := spanWidth(widths, i, span)
+		t.drawCell(doc, x, startY, w, rowH, paddingH, paddingV, lineH, font, t.Columns[i], cell)
+		x += w
+		i += span
 	}
 
 	// Step 3: Draw outer row rect + internal column separators.
-	doc.applyColor(doc.theme.TableBorderColor)
-	doc.pdf.Rect(startX, startY, totalW, rowH, "D")
-	x := startX
-	for i := 0; i < len(widths)-1; i++ {
-		x += widths[i]
-		doc.pdf.Line(x, startY, x, startY+rowH)
-	}
+	t.drawBorders(doc, row, startX, startY, widths, rowH, "columns")
 
 	doc.setY(startY + rowH)
 }
 
 // resolveColumnWidths distributes usable width among columns.
-// Fixed-width columns are allocated first; remaining space is split equally
-// among columns with Width == 0.
+// Fixed-width columns are allocated first; remaining space is split among
+// columns with Width == 0 in proportion to their Flex, equally by default.
 func (t *TableComponent) resolveColumnWidths(usableWidth float64) []float64 {
 	widths := make([]float64, len(t.Columns))
 	remaining := usableWidth
-	autoCount := 0
+	flex := 0.0
 
 	for i, col := range t.Columns {
 		if col.Width > 0 {
 			widths[i] = col.Width
 			remaining -= col.Width
 		} else {
-			autoCount++
+			flex += flexOf(col)
 		}
 	}
 
-	if autoCount > 0 {
-		autoW := remaining / float64(autoCount)
-		for i := range t.Columns {
-			if t.Columns[i].Width == 0 {
-				widths[i] = autoW
+	if flex > 0 {
+		for i, col := range t.Columns {
+			if col.Width == 0 {
+				widths[i] = remaining * flexOf(col) / flex
 			}
 		}
 	}
@@ -261,22 +460,24 @@ func (t *TableComponent) resolveColumnWidths(usableWidth float64) []float64 {
 
 // calcRowHeight returns the required row height in mm, accounting for wrapped
 // cells (OverflowWrap). Returns 0 if no cell needs more than a single line.
-func (t *TableComponent) calcRowHeight(doc *Document, row []string, widths []float64, paddingH, paddingV, lineH float64, font FontConfig) float64 {
+func (t *TableComponent) calcRowHeight(doc *Document, row []Cell, widths []float64, paddingH, paddingV, lineH float64, font FontConfig) float64 {
 	maxContentH := 0.0
 	doc.applyFont(font)
 
-	for i, col := range t.Columns {
-		if i >= len(row) {
-			break
-		}
+	for i := 0; i < len(t.Columns) && i < len(row); {
+		col, cell := t.Columns[i], row[i]
+		span := cellSpan(cell, i, len(t.Columns))
+		w := spanWidth(widths, i, span)
+		i += span
 		if col.Overflow != OverflowWrap {
 			continue
 		}
-		cellW := widths[i] - 2*paddingH
+		doc.applyFont(cellFont(font, col, cell))
+		cellW := w - 2*paddingH - decorWidth(doc, cell, cellFont(font, col, cell))
 		if cellW <= 0 {
 			continue
 		}
-		lines := doc.pdf.SplitLines([]byte(row[i]), cellW)
+		lines := doc.splitLines(cell.Text, cellW)
 		h := float64(len(lines)) * lineH
 		if h > maxContentH {
 			maxContentH = h
@@ -289,37 +490,7 @@ func (t *TableComponent) calcRowHeight(doc *Document, row []string, widths []flo
 	return maxContentH + 2*paddingV
 }
 
-func (t *TableComponent) renderHeaderRow(doc *Document, widths []float64, paddingH, paddingV, rowH float64, font FontConfig, borderStyle string) {
-	startY := doc.currentY()
-	startX := doc.marginL
-
-	// Background
-	doc.applyColor(doc.theme.TableHeaderBg)
-	doc.pdf.Rect(startX, startY, doc.usableWidth(), rowH, "F")
-
-	// Text — use HeaderTextColor (muted gray) to match the reference design
-	doc.applyFont(font)
-	doc.applyTextColor(doc.theme.HeaderTextColor)
-
-	x := startX
-	for i, col := range t.Columns {
-		align := col.HeaderAlign
-		if align == "" {
-			align = col.Align
-		}
-		if align == "" {
-			align = "L"
-		}
-		doc.pdf.SetXY(x+paddingH, startY+paddingV)
-		doc.pdf.CellFormat(widths[i]-2*paddingH, rowH-2*paddingV, col.Header, "", 0, align, false, 0, "")
-		x += widths[i]
-	}
-
-	t.drawBorders(doc, startX, startY, widths, rowH, borderStyle)
-	doc.setY(startY + rowH)
-}
-
-func (t *TableComponent) renderDataRow(doc *Document, row []string, bgColor Color, widths []float64, paddingH, paddingV, rowH, lineH float64, font FontConfig, borderStyle string) {
+func (t *TableComponent) renderDataRow(doc *Document, row []Cell, bgColor Color, widths []float64, paddingH, paddingV, rowH, lineH float64, font FontConfig, borderStyle string) {
 	startY := doc.currentY()
 	startX := doc.marginL
 
@@ -328,64 +499,65 @@ func (t *TableComponent) renderDataRow(doc *Document, row []string, bgColor Colo
 	doc.pdf.Rect(startX, startY, doc.usableWidth(), rowH, "F")
 
 	x := startX
-	for i, col := range t.Columns {
-		text := ""
+	for i := 0; i < len(t.Columns); {
+		var cell Cell
 		if i < len(row) {
-			text = row[i]
+			cell = row[i]
 		}
-
-		align := col.Align
-		if align == "" {
-			align = "L"
-		}
-
-		cellFont := font
-		if col.Bold {
-			cellFont.Style = "B"
-		}
-		doc.applyFont(cellFont)
-		doc.applyTextColor(doc.theme.PrimaryText)
-
-		cellW := widths[i] - 2*paddingH
-
-		if col.Overflow == OverflowWrap {
-			doc.pdf.SetXY(x+paddingH, startY+paddingV)
-			doc.pdf.MultiCell(cellW, lineH, text, "", align, false)
-		} else {
-			if col.Overflow == OverflowTruncate {
-				text = truncateText(doc, text, cellW)
-			}
-			doc.pdf.SetXY(x+paddingH, startY+paddingV)
-			doc.pdf.CellFormat(cellW, rowH-2*paddingV, text, "", 0, align, false, 0, "")
-		}
-
-		x += widths[i]
+		span := cellSpan(cell, i, len(t.Columns))
+		w := spanWidth(widths, i, span)
+		t.drawCell(doc, x, startY, w, rowH, paddingH, paddingV, lineH, font, t.Columns[i], cell)
+		x += w
+		i += span
 	}
 
-	t.drawBorders(doc, startX, startY, widths, rowH, borderStyle)
+	t.drawBorders(doc, row, startX, startY, widths, rowH, borderStyle)
 	doc.setY(startY + rowH)
 }
 
-// drawBorders draws cell borders according to the BorderStyle.
-func (t *TableComponent) drawBorders(doc *Document, startX, startY float64, widths []float64, rowH float64, borderStyle string) {
+// drawBorders draws cell borders according to the BorderStyle. Cells
+// spanning several columns get a single border.
+func (t *TableComponent) drawBorders(doc *Document, row []Cell, startX, startY float64, widths []float64, rowH float64, borderStyle string) {
 	doc.applyColor(doc.theme.TableBorderColor)
 
 	switch borderStyle {
 	case "all":
 		x := startX
-		for _, w := range widths {
+		for i := 0; i < len(widths); {
+			var cell Cell
+			if i < len(row) {
+				cell = row[i]
+			}
+			span := cellSpan(cell, i, len(widths))
+			w := spanWidth(widths, i, span)
 			doc.pdf.Rect(x, startY, w, rowH, "D")
 			x += w
+			i += span
 		}
 	case "outer":
 		doc.pdf.Rect(startX, startY, doc.usableWidth(), rowH, "D")
+	case "columns":
+		doc.pdf.Rect(startX, startY, doc.usableWidth(), rowH, "D")
+		x := startX
+		for i := 0; i < len(widths); {
+			var cell Cell
+			if i < len(row) {
+				cell = row[i]
+			}
+			span := cellSpan(cell, i, len(widths))
+			x += spanWidth(widths, i, span)
+			i += span
+			if i < len(widths) {
+				doc.pdf.Line(x, startY, x, startY+rowH)
+			}
+		}
 	}
 }
 
 // truncateText clips text and appends "…" so it fits within maxW mm using
 // the currently active font.
 func truncateText(doc *Document, text string, maxW float64) string {
-	if doc.pdf.GetStringWidth(text) <= maxW {
+	if doc.stringWidth(text) <= maxW {
 		return text
 	}
 	const ellipsis = "…"
@@ -393,7 +565,7 @@ func truncateText(doc *Document, text string, maxW float64) string {
 	for len(runes) > 0 {
 		runes = runes[:len(runes)-1]
 		candidate := string(runes) + ellipsis
-		if doc.pdf.GetStringWidth(candidate) <= maxW {
+		if doc.stringWidth(candidate) <= maxW {
 			return candidate
 		}
 	}
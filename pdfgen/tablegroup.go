@@ -0,0 +1,121 @@
+package pdfgen
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkGroupBy reports GroupBy indexes outside the column range.
+func (t *TableComponent) checkGroupBy() error {
+	for _, k := range t.GroupBy {
+		if k < 0 || k >= len(t.Columns) {
+			return fmt.Errorf("pdfgen: TableComponent GroupBy column %d out of range [0, %d)", k, len(t.Columns))
+		}
+	}
+	return nil
+}
+
+// groupLabel returns the header band text for a group key.
+func (t *TableComponent) groupLabel(key []string) string {
+	if t.GroupLabel != nil {
+		return t.GroupLabel(key)
+	}
+	parts := make([]string, 0, len(key))
+	for _, k := range key {
+		if k != "" {
+			parts = append(parts, k)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+func equalKeys(a, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// withoutGroupColumns returns a copy of t without the GroupBy columns and
+// the set of hidden column indexes. Header groups shrink by the hidden
+// columns they covered.
+func (t *TableComponent) withoutGroupColumns() (*TableComponent, map[int]bool) {
+	hidden := make(map[int]bool, len(t.GroupBy))
+	for _, k := range t.GroupBy {
+		hidden[k] = true
+	}
+
+	v := *t
+	v.Columns = nil
+	for i, col := range t.Columns {
+		if !hidden[i] {
+			v.Columns = append(v.Columns, col)
+		}
+	}
+	v.HeaderGroups = make([][]HeaderGroup, len(t.HeaderGroups))
+	for r, row := range t.HeaderGroups {
+		c := 0
+		for _, hg := range row {
+			span := hg.Span
+			if span < 1 {
+				span = 1
+			}
+			hg.Span = 0
+			for k := c; k < c+span; k++ {
+				if !hidden[k] {
+					hg.Span++
+				}
+			}
+			if hg.Span > 0 {
+				v.HeaderGroups[r] = append(v.HeaderGroups[r], hg)
+			}
+			c += span
+		}
+	}
+	return &v, hidden
+}
+
+// hideColumns drops the hidden columns from a row. A span loses the hidden
+// columns it covered.
+func hideColumns(row []Cell, hidden map[int]bool) []Cell {
+	out := make([]Cell, 0, len(row))
+	for i, cell := range row {
+		if hidden[i] {
+			continue
+		}
+		if span := cellSpan(cell, i, len(row)); span > 1 {
+			cell.Span = 0
+			for k := i; k < i+span; k++ {
+				if !hidden[k] {
+					cell.Span++
+				}
+			}
+		}
+		out = append(out, cell)
+	}
+	return out
+}
+
+// renderGroupHeader draws a full-width group header band.
+func (t *TableComponent) renderGroupHeader(doc *Document, lay *tableLayout, label string) {
+	startY := doc.currentY()
+	startX := doc.marginL
+	totalW := doc.usableWidth()
+
+	doc.applyColor(doc.theme.TableRowEvenBg)
+	doc.pdf.Rect(startX, startY, totalW, lay.minRowH, "F")
+
+	doc.applyFont(lay.groupFont)
+	doc.applyTextColor(doc.theme.SectionLabelLeft)
+	cellW := totalW - 2*lay.paddingH
+	doc.pdf.SetXY(startX+lay.paddingH, startY+lay.paddingV)
+	doc.cellFormat(cellW, lay.minRowH-2*lay.paddingV, truncateText(doc, label, cellW), "", 0, "L", false)
+
+	if lay.borderStyle != "none" {
+		doc.applyColor(doc.theme.TableBorderColor)
+		doc.pdf.Rect(startX, startY, totalW, lay.minRowH, "D")
+	}
+	doc.setY(startY + lay.minRowH)
+}
@@ -0,0 +1,172 @@
+package pdfgen
+
+import "fmt"
+
+// HeaderGroup is a header cell spanning several columns above the column
+// headers, e.g. "Odometer" over "Start | End | Miles".
+type HeaderGroup struct {
+	Title string // empty → the header cells below extend up into this row
+	Span  int    // number of columns covered; default 1
+	Align string // "L", "C", "R"; default "C"
+}
+
+// headerCell is one resolved header cell. It occupies header rows
+// top..bottom (inclusive) and the columns starting at x with width w.
+type headerCell struct {
+	title       string
+	align       string
+	x, w        float64
+	col, span   int // first column and number of columns covered
+	top, bottom int
+	group       bool // a HeaderGroup rather than a column header
+}
+
+// headerCells resolves HeaderGroups and the column headers into cells and
+// returns them with the number of header rows. Each HeaderGroups row must
+// cover every column, and a group may not straddle a group of the row above.
+func (t *TableComponent) headerCells(widths []float64) ([]headerCell, int, error) {
+	n := len(t.Columns)
+	// owner[r][c] is the index in t.HeaderGroups[r] of the group covering column c.
+	owner := make([][]int, len(t.HeaderGroups))
+	for r, row := range t.HeaderGroups {
+		owner[r] = make([]int, 0, n)
+		for g, hg := range row {
+			span := hg.Span
+			if span < 1 {
+				span = 1
+			}
+			for k := 0; k < span; k++ {
+				owner[r] = append(owner[r], g)
+			}
+		}
+		if len(owner[r]) != n {
+			return nil, 0, fmt.Errorf("pdfgen: TableComponent HeaderGroups row %d spans %d columns, want %d", r, len(owner[r]), n)
+		}
+		if r == 0 {
+			continue
+		}
+		for c := 1; c < n; c++ {
+			if owner[r][c] == owner[r][c-1] && owner[r-1][c] != owner[r-1][c-1] {
+				return nil, 0, fmt.Errorf("pdfgen: TableComponent HeaderGroups row %d group %q crosses a group boundary of row %d", r, row[owner[r][c]].Title, r-1)
+			}
+		}
+	}
+
+	// top returns the highest header row a cell in row r starting at column
+	// c reaches by extending up through untitled groups.
+	top := func(r, c int) int {
+		for r > 0 && t.HeaderGroups[r-1][owner[r-1][c]].Title == "" {
+			r--
+		}
+		return r
+	}
+
+	var cells []headerCell
+	for r, row := range t.HeaderGroups {
+		c := 0
+		for _, hg := range row {
+			span := hg.Span
+			if span < 1 {
+				span = 1
+			}
+			if hg.Title != "" {
+				align := hg.Align
+				if align == "" {
+					align = "C"
+				}
+				cells = append(cells, headerCell{
+					title:  hg.Title,
+					align:  align,
+					x:      spanWidth(widths, 0, c),
+					w:      spanWidth(widths, c, span),
+					col:    c,
+					span:   span,
+					top:    top(r, c),
+					bottom: r,
+					group:  true,
+				})
+			}
+			c += span
+		}
+	}
+	last := len(t.HeaderGroups)
+	for c, col := range t.Columns {
+		align := col.HeaderAlign
+		if align == "" {
+			align = columnAlign(col)
+		}
+		cells = append(cells, headerCell{
+			title:  col.Header,
+			align:  align,
+			x:      spanWidth(widths, 0, c),
+			w:      widths[c],
+			col:    c,
+			span:   1,
+			top:    top(last, c),
+			bottom: last,
+		})
+	}
+	return cells, last + 1, nil
+}
+
+// renderHeader draws the header rows in the table's border style.
+func (t *TableComponent) renderHeader(doc *Document, lay *tableLayout) {
+	if !t.ShowHeader {
+		return
+	}
+	startY := doc.currentY()
+	startX := doc.marginL
+	totalW := doc.usableWidth()
+	rowH := lay.minRowH
+	h := float64(lay.headerRows) * rowH
+
+	// Background
+	doc.applyColor(doc.theme.TableHeaderBg)
+	doc.pdf.Rect(startX, startY, totalW, h, "F")
+
+	// Text — use HeaderTextColor (muted gray) to match the reference design
+	doc.applyFont(lay.headerFont)
+	doc.applyTextColor(doc.theme.HeaderTextColor)
+	for _, c := range lay.header {
+		y := startY + float64(c.top)*rowH
+		ch := float64(c.bottom-c.top+1) * rowH
+		doc.pdf.SetXY(startX+c.x+lay.paddingH, y+lay.paddingV)
+		doc.cellFormat(c.w-2*lay.paddingH, ch-2*lay.paddingV, c.title, "", 0, c.align, false)
+	}
+
+	doc.applyColor(doc.theme.TableBorderColor)
+	switch lay.borderStyle {
+	case "all":
+		for _, c := range lay.header {
+			y := startY + float64(c.top)*rowH
+			doc.pdf.Rect(startX+c.x, y, c.w, float64(c.bottom-c.top+1)*rowH, "D")
+		}
+	case "columns":
+		// Outer rect, separators between header cells, a rule under each
+		// group, and the header bottom separator line.
+		doc.pdf.Rect(startX, startY, totalW, h, "D")
+		for _, c := range lay.header {
+			y := startY + float64(c.top)*rowH
+			bottom := startY + float64(c.bottom+1)*rowH
+			if c.x > 0 {
+				doc.pdf.Line(startX+c.x, y, startX+c.x, bottom)
+			}
+			if c.group {
+				doc.pdf.Line(startX+c.x, bottom, startX+c.x+c.w, bottom)
+			}
+		}
+		doc.pdf.Line(startX, startY+h, startX+totalW, startY+h)
+	default:
+		if lay.borderStyle == "outer" {
+			doc.pdf.Rect(startX, startY, totalW, h, "D")
+		}
+		// Without column lines, a short rule under each group shows its span.
+		for _, c := range lay.header {
+			if c.group {
+				bottom := startY + float64(c.bottom+1)*rowH
+				doc.pdf.Line(startX+c.x+lay.paddingH, bottom, startX+c.x+c.w-lay.paddingH, bottom)
+			}
+		}
+	}
+	doc.setY(startY + h)
+}
@@ -7,7 +7,7 @@ type Color struct {
 
 // FontConfig describes a font family, size, and style.
 type FontConfig struct {
-	Family string  // e.g. "Arial", "Helvetica"
+	Family string  // e.g. "Arial", "Helvetica", or a DocumentConfig.Fonts name
 	Size   float64 // points
 	Style  string  // "", "B", "I", "BI"
 }
@@ -24,6 +24,7 @@ type ThemeConfig struct {
 	HeaderTextColor    Color
 	SectionLabelLeft   Color // label part before ":" in section label right text; #334155 slate-700
 	SectionLabelValue  Color // value part after ":" in section label right text;  #94A3B8 slate-400
+	ChartPalette       []Color // series colors for charts; empty → built-in palette
 	DefaultFont        FontConfig
 }
 
@@ -0,0 +1,252 @@
+package pdfgen
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// TableOfContentsComponent lists the document's bookmarks with their page
+// numbers. It reserves Pages pages where it is added and is filled in when
+// the document is saved, once every section's page is known; the content
+// after it starts on a new page. A list longer than the reserved pages
+// continues on pages inserted after them. Entries link to their sections.
+type TableOfContentsComponent struct {
+	Title     string     // default "Contents"
+	Depth     int        // outline levels listed, e.g. 2 → levels 0 and 1; default all
+	Pages     int        // pages reserved for the list; default 1; more are inserted as needed
+	TitleFont FontConfig // zero value → 14pt bold
+	EntryFont FontConfig // zero value → theme default; top-level entries bold
+	Indent    float64    // mm per outline level; default 5
+}
+
+const tocTitleH, tocRowH = 10.0, 6.0
+
+// tocSlot is the space a TableOfContentsComponent reserved: pages, the
+// first from tops[0], the others from tops[i]. Pages beyond reserved are
+// added by grow at the end of the document and output after the reserved
+// ones. sec, header, footer and vars are those of the reserved pages.
+type tocSlot struct {
+	toc      TableOfContentsComponent
+	pages    []int
+	tops     []float64
+	reserved int
+	sec      *section
+	header   *PageHeaderComponent
+	footer   *FooterComponent
+	vars     map[string]string
+}
+
+// Render reserves the pages of the table of contents.
+func (t *TableOfContentsComponent) Render(doc *Document) error {
+	pages := t.Pages
+	if pages < 1 {
+		pages = 1
+	}
+	slot := tocSlot{
+		toc:      *t,
+		pages:    []int{doc.pdf.PageNo()},
+		tops:     []float64{doc.currentY()},
+		reserved: pages,
+		sec:      doc.sec,
+		header:   doc.header,
+		footer:   doc.footer,
+		vars:     doc.vars,
+	}
+	for i := 0; i < pages; i++ {
+		doc.addPage()
+		if i < pages-1 {
+			slot.pages = append(slot.pages, doc.pdf.PageNo())
+			slot.tops = append(slot.tops, doc.pageTop)
+		}
+	}
+	doc.tocs = append(doc.tocs, slot)
+	return nil
+}
+
+// entries returns the outline entries the table lists.
+func (s *tocSlot) entries(d *Document) []outlineEntry {
+	var entries []outlineEntry
+	for _, e := range d.outline {
+		if s.toc.Depth == 0 || e.level < s.toc.Depth {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// rows returns how many entries fit on the i-th page of the table.
+func (s *tocSlot) rows(d *Document, i int) int {
+	top := s.tops[i]
+	if i == 0 {
+		top += tocTitleH + 2
+	}
+	bottom := d.bottomOf(d.page(s.pages[i]).sec, s.footer)
+	return max(int(math.Floor((bottom-top+1e-9)/tocRowH)), 0)
+}
+
+// grow adds pages at the end of the document until every entry fits. They
+// are moved behind the reserved pages when the PDF is written, and the
+// page numbers count them there.
+func (s *tocSlot) grow(d *Document) error {
+	need := len(s.entries(d))
+	fit := 0
+	for i := range s.pages {
+		fit += s.rows(d, i)
+	}
+	for fit < need {
+		header := d.header
+		d.pdf.SetPage(d.pdf.PageCount())
+		d.useSection(s.sec)
+		d.header = s.header
+		d.addPage()
+		d.header = header
+		s.pages = append(s.pages, d.pdf.PageNo())
+		s.tops = append(s.tops, d.pageTop)
+		n := s.rows(d, len(s.pages)-1)
+		if n == 0 {
+			return fmt.Errorf("pdfgen: TableOfContentsComponent: no entry fits on a page of its section")
+		}
+		fit += n
+	}
+	return nil
+}
+
+// fill draws the title and entries into the table's pages. numbers are
+// the printed page numbers, indexed by page-1.
+func (s *tocSlot) fill(d *Document, numbers []int) {
+	t := s.toc
+	title := t.Title
+	if title == "" {
+		title = "Contents"
+	}
+	titleFont := t.TitleFont
+	if titleFont.Family == "" {
+		titleFont = FontConfig{Family: d.theme.DefaultFont.Family, Size: 14, Style: "B"}
+	}
+	entryFont := t.EntryFont
+	if entryFont.Family == "" {
+		entryFont = d.theme.DefaultFont
+	}
+	indent := t.Indent
+	if indent == 0 {
+		indent = 5
+	}
+
+	i, y := 0, s.tops[0]
+	d.setPage(s.pages[0])
+	d.applyFont(titleFont)
+	d.applyTextColor(d.theme.PrimaryText)
+	d.pdf.SetXY(d.marginL, y)
+	d.cellFormat(d.usableWidth(), tocTitleH, title, "", 0, "L", false)
+	y += tocTitleH + 2
+
+	left := s.rows(d, 0)
+	for _, e := range s.entries(d) {
+		for left == 0 {
+			i++
+			d.setPage(s.pages[i])
+			y, left = s.tops[i], s.rows(d, i)
+		}
+		font := entryFont
+		if e.level == 0 && font.Style == "" {
+			font.Style = "B"
+		}
+		drawTOCEntry(d, e, numbers[e.page-1], font, d.marginL+float64(e.level)*indent, y, tocRowH)
+		y += tocRowH
+		left--
+	}
+}
+
+// pageOrder returns the pages in output order, with the pages added by
+// tables of contents behind the ones they reserved, or nil when no table
+// grew.
+func (d *Document) pageOrder() []int {
+	after := make(map[int][]int)
+	added := make(map[int]bool)
+	for _, s := range d.tocs {
+		extra := s.pages[s.reserved:]
+		if len(extra) == 0 {
+			continue
+		}
+		after[s.pages[s.reserved-1]] = extra
+		for _, n := range extra {
+			added[n] = true
+		}
+	}
+	if len(added) == 0 {
+		return nil
+	}
+	order := make([]int, 0, d.pdf.PageCount())
+	for n := 1; n <= d.pdf.PageCount(); n++ {
+		if added[n] {
+			continue
+		}
+		order = append(order, n)
+		order = append(order, after[n]...)
+	}
+	return order
+}
+
+// reorderPages rewrites the page tree of a PDF written by fpdf so its pages
+// appear in order. Links and bookmarks point at page objects, not
+// positions, and the tree keeps its length, so nothing else changes.
+func reorderPages(pdf []byte, order []int) ([]byte, error) {
+	const kids = "/Type /Pages\n/Kids ["
+	i := bytes.Index(pdf, []byte(kids))
+	if i < 0 {
+		return nil, fmt.Errorf("pdfgen: page tree not found")
+	}
+	i += len(kids)
+	j := bytes.IndexByte(pdf[i:], ']')
+	if j < 0 {
+		return nil, fmt.Errorf("pdfgen: page tree not found")
+	}
+	refs := strings.Fields(string(pdf[i : i+j]))
+	if len(refs) != 3*len(order) {
+		return nil, fmt.Errorf("pdfgen: page tree has %d pages, want %d", len(refs)/3, len(order))
+	}
+	var b strings.Builder
+	for _, n := range order {
+		b.WriteString(strings.Join(refs[3*(n-1):3*n], " ") + " ")
+	}
+	if b.Len() != j {
+		return nil, fmt.Errorf("pdfgen: page tree changed length")
+	}
+	out := bytes.Clone(pdf)
+	copy(out[i:], b.String())
+	return out, nil
+}
+
+// drawTOCEntry draws one entry — title, dot leaders and printed page number
+// — from x to the right margin, linked to the entry's position.
+func drawTOCEntry(d *Document, e outlineEntry, number int, font FontConfig, x, y, h float64) {
+	w := d.marginL + d.usableWidth() - x
+	cm := d.pdf.GetCellMargin()
+	num := strconv.Itoa(number)
+
+	d.applyFont(font)
+	numW := d.stringWidth(num)
+	title := truncateText(d, e.title, w-2*cm-numW-6)
+	titleW := d.stringWidth(title)
+
+	d.applyTextColor(d.theme.PrimaryText)
+	d.pdf.SetXY(x, y)
+	d.cellFormat(w, h, title, "", 0, "L", false)
+	d.pdf.SetXY(x, y)
+	d.cellFormat(w, h, num, "", 0, "R", false)
+
+	// Dot leaders between the title and the page number.
+	gap := w - 2*cm - titleW - numW - 4
+	if dotW := d.stringWidth(". "); gap > dotW {
+		d.applyTextColor(d.theme.SecondaryText)
+		d.pdf.SetXY(x+titleW+2, y)
+		d.cellFormat(gap+2*cm, h, strings.Repeat(". ", int(gap/dotW)), "", 0, "R", false)
+	}
+
+	link := d.pdf.AddLink()
+	d.pdf.SetLink(link, e.y, e.page)
+	d.pdf.Link(x, y+d.pageShift, w, h, link)
+}
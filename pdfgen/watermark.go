@@ -0,0 +1,129 @@
+package pdfgen
+
+import (
+	"fmt"
+	"math"
+)
+
+// Watermark is rotated, semi-transparent text or an image drawn on every
+// page or a range of pages, e.g. "DRAFT" across uncertified logs or a
+// carrier seal. With Border, text becomes a rubber stamp such as a boxed
+// "VOID". Register watermarks via doc.AddWatermark().
+type Watermark struct {
+	Text      string     // e.g. "DRAFT"; ignored when an image is set
+	Font      FontConfig // zero value → theme family, 60pt bold
+	Color     Color      // text and border color; zero value → theme SecondaryText
+	Border    bool       // draw a rounded box around the text, like a stamp
+	ImageData []byte     // PNG/JPG bytes (alternative to Text)
+	ImagePath string     // PNG/JPG file (alternative to Text)
+	Width     float64    // image width in mm; default 80; height keeps the aspect ratio
+	Angle     float64    // degrees counterclockwise, e.g. 45; default 0 (horizontal)
+	Opacity   float64    // 0–1; default 0.15
+	X, Y      float64    // center in mm from the top-left page corner; zero → page center
+	Over      bool       // draw above the content; default underneath
+	FirstPage int        // first page marked, 1-based; default 1
+	LastPage  int        // last page marked; default the last page
+}
+
+// AddWatermark marks the pages in w's range. Watermarks drawn underneath the
+// content start with the next page, or with page 1 when nothing has been
+// added yet; watermarks drawn over the content apply to every page in range.
+func (d *Document) AddWatermark(w Watermark) *Document {
+	if w.Text == "" && w.ImagePath == "" && len(w.ImageData) == 0 {
+		if d.err == nil {
+			d.err = fmt.Errorf("pdfgen: Watermark requires Text, ImageData or ImagePath")
+		}
+		return d
+	}
+	d.watermarks = append(d.watermarks, &w)
+	if !w.Over && d.pdf.PageNo() == 1 && d.currentY() == d.pageTop {
+		// Nothing has flowed onto the first page yet.
+		x, y := d.pdf.GetXY()
+		w.draw(d, 1)
+		d.pdf.SetXY(x, y)
+	}
+	return d
+}
+
+// drawWatermarks draws the watermarks of page n that go over or under the
+// content.
+func (d *Document) drawWatermarks(n int, over bool) {
+	for _, w := range d.watermarks {
+		if w.Over == over {
+			w.draw(d, n)
+		}
+	}
+}
+
+// draw draws the watermark if page n is in its range, leaving the graphics
+// state as it was.
+func (w *Watermark) draw(d *Document, n int) {
+	if n < w.FirstPage || (w.LastPage > 0 && n > w.LastPage) {
+		return
+	}
+	pdf := d.pdf
+	cx, cy := w.X, w.Y
+	if cx == 0 {
+		cx = d.sec.w / 2
+	}
+	if cy == 0 {
+		cy = d.sec.h / 2
+	}
+	opacity := w.Opacity
+	if opacity == 0 {
+		opacity = 0.15
+	}
+	color := w.Color
+	if color == (Color{}) {
+		color = d.theme.SecondaryText
+	}
+
+	x, y := pdf.GetXY()
+	lw := pdf.GetLineWidth()
+	pdf.SetAlpha(opacity, "Normal")
+	pdf.TransformBegin()
+	pdf.TransformRotate(w.Angle, cx, cy)
+
+	if w.ImagePath != "" || len(w.ImageData) > 0 {
+		width := w.Width
+		if width == 0 {
+			width = 80
+		}
+		name, opts := d.image(w.ImagePath, w.ImageData)
+		pdf.RegisterImageOptions(name, opts)
+		height := width
+		if info := pdf.GetImageInfo(name); info != nil && info.Width() > 0 {
+			height = width * info.Height() / info.Width()
+		}
+		pdf.ImageOptions(name, cx-width/2, cy-height/2, width, height, false, opts, 0, "")
+	} else {
+		font := w.Font
+		if font.Family == "" {
+			font.Family = d.theme.DefaultFont.Family
+			if font.Style == "" {
+				font.Style = "B"
+			}
+		}
+		if font.Size == 0 {
+			font.Size = 60
+		}
+		d.applyFont(font)
+		d.applyTextColor(color)
+		cm := pdf.GetCellMargin()
+		textW := d.stringWidth(w.Text) + 2*cm
+		textH := font.Size * 25.4 / 72 // cap height plus descent fit in one em
+		pdf.SetXY(cx-textW/2, cy-textH/2)
+		d.cellFormat(textW, textH, w.Text, "", 0, "C", false)
+		if w.Border {
+			pad := textH * 0.15
+			d.applyColor(color)
+			pdf.SetLineWidth(math.Max(0.5, textH*0.06))
+			pdf.RoundedRect(cx-textW/2-pad, cy-textH/2-pad, textW+2*pad, textH+2*pad, textH*0.15, "1234", "D")
+		}
+	}
+
+	pdf.TransformEnd()
+	pdf.SetAlpha(1, "Normal")
+	pdf.SetLineWidth(lw)
+	pdf.SetXY(x, y)
+}